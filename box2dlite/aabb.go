@@ -0,0 +1,58 @@
+package box2dlite
+
+import (
+	"math"
+)
+
+// AABB is an axis-aligned bounding box in world space.
+type AABB struct {
+	Min, Max Vec2
+}
+
+func (a *AABB) Overlaps(o AABB) bool {
+	if o.Min.X > a.Max.X || o.Min.Y > a.Max.Y {
+		return false
+	}
+	if a.Min.X > o.Max.X || a.Min.Y > o.Max.Y {
+		return false
+	}
+	return true
+}
+
+// Contains reports whether o lies entirely inside a.
+func (a *AABB) Contains(o AABB) bool {
+	return a.Min.X <= o.Min.X && a.Min.Y <= o.Min.Y && o.Max.X <= a.Max.X && o.Max.Y <= a.Max.Y
+}
+
+func (a *AABB) Combine(o AABB) AABB {
+	return AABB{
+		Vec2{math.Min(a.Min.X, o.Min.X), math.Min(a.Min.Y, o.Min.Y)},
+		Vec2{math.Max(a.Max.X, o.Max.X), math.Max(a.Max.Y, o.Max.Y)},
+	}
+}
+
+func (a *AABB) Center() Vec2 {
+	return Vec2{
+		0.5 * (a.Min.X + a.Max.X),
+		0.5 * (a.Min.Y + a.Max.Y),
+	}
+}
+
+// Extents returns the half size of the box.
+func (a *AABB) Extents() Vec2 {
+	return Vec2{
+		0.5 * (a.Max.X - a.Min.X),
+		0.5 * (a.Max.Y - a.Min.Y),
+	}
+}
+
+// Perimeter returns the perimeter of the box, the cost measure of the dynamic tree.
+func (a *AABB) Perimeter() float64 {
+	return 2.0 * ((a.Max.X - a.Min.X) + (a.Max.Y - a.Min.Y))
+}
+
+// Grow returns the box pushed out by margin on every side.
+func (a *AABB) Grow(margin float64) AABB {
+	r := Vec2{margin, margin}
+	return AABB{a.Min.Sub(r), a.Max.Add(r)}
+}
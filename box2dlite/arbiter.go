@@ -37,20 +37,21 @@ type Contact struct {
 	Pnb                     float64 // accumulated normal impulse for position bias
 	MassNormal, MassTangent float64
 	Bias                    float64
+	RelativeVelocity        float64 // normal velocity before solving, for restitution
 	Feature                 FeaturePair
 }
 
 type ArbiterKey struct {
-	Body1, Body2 *Body
+	Fixture1, Fixture2 *Fixture
 }
 
-func (k *ArbiterKey) Set(b1, b2 *Body) {
-	if uintptr(unsafe.Pointer(b1)) < uintptr(unsafe.Pointer(b2)) {
-		k.Body1 = b1
-		k.Body2 = b2
+func (k *ArbiterKey) Set(f1, f2 *Fixture) {
+	if uintptr(unsafe.Pointer(f1)) < uintptr(unsafe.Pointer(f2)) {
+		k.Fixture1 = f1
+		k.Fixture2 = f2
 	} else {
-		k.Body1 = b2
-		k.Body2 = b1
+		k.Fixture1 = f2
+		k.Fixture2 = f1
 	}
 }
 
@@ -59,11 +60,11 @@ type ArbiterKeys []ArbiterKey
 func (k ArbiterKeys) Less(i, j int) bool {
 	a1 := k[i]
 	a2 := k[j]
-	if uintptr(unsafe.Pointer(a1.Body1)) < uintptr(unsafe.Pointer(a2.Body1)) {
+	if uintptr(unsafe.Pointer(a1.Fixture1)) < uintptr(unsafe.Pointer(a2.Fixture1)) {
 		return true
 	}
 
-	if (a1.Body1 == a2.Body1) && uintptr(unsafe.Pointer(a1.Body2)) < uintptr(unsafe.Pointer(a2.Body2)) {
+	if (a1.Fixture1 == a2.Fixture1) && uintptr(unsafe.Pointer(a1.Fixture2)) < uintptr(unsafe.Pointer(a2.Fixture2)) {
 		return true
 	}
 
@@ -85,24 +86,40 @@ const (
 type Arbiter struct {
 	Contacts []*Contact
 
-	Body1, Body2 *Body
+	Fixture1, Fixture2 *Fixture
+	Body1, Body2       *Body
 
 	// Combined friction
 	Friction float64
+
+	// Combined restitution
+	Restitution float64
+
+	// Approach speed below which the contacts do not bounce
+	restitutionThreshold float64
+
+	// The solver skips a disabled arbiter but keeps its contacts for warm
+	// starting. Arbiters are enabled again at the start of each step,
+	// before ContactListener.PreSolve.
+	Enabled bool
 }
 
-func (a *Arbiter) Set(b1 *Body, b2 *Body, contacts []*Contact) {
-	if uintptr(unsafe.Pointer(b1)) < uintptr(unsafe.Pointer(b2)) {
-		a.Body1 = b1
-		a.Body2 = b2
+func (a *Arbiter) Set(f1 *Fixture, f2 *Fixture, contacts []*Contact) {
+	if uintptr(unsafe.Pointer(f1)) < uintptr(unsafe.Pointer(f2)) {
+		a.Fixture1 = f1
+		a.Fixture2 = f2
 	} else {
-		a.Body1 = b2
-		a.Body2 = b1
+		a.Fixture1 = f2
+		a.Fixture2 = f1
 	}
+	a.Body1 = a.Fixture1.Body
+	a.Body2 = a.Fixture2.Body
 
 	a.Contacts = contacts
 
-	a.Friction = math.Sqrt(b1.Friction * b2.Friction)
+	a.Friction = math.Sqrt(a.Body1.Friction * a.Body2.Friction)
+	a.Restitution = math.Max(a.Body1.Restitution, a.Body2.Restitution)
+	a.Enabled = true
 }
 
 func (a *Arbiter) Update(newContacts []*Contact) {
@@ -135,6 +152,27 @@ func (a *Arbiter) Update(newContacts []*Contact) {
 	a.Contacts = mergedContacts
 }
 
+// StoreRelativeVelocity records the normal velocity of each contact for
+// restitution. Contacts approaching slower than threshold do not bounce.
+// Call it for all arbiters before any PreStep: the warm starting impulses
+// would make resting contacts look like impacts.
+func (a *Arbiter) StoreRelativeVelocity(threshold float64) {
+	a.restitutionThreshold = threshold
+
+	center1 := a.Body1.WorldCenter()
+	center2 := a.Body2.WorldCenter()
+
+	for _, c := range a.Contacts {
+		r1 := c.Position.Sub(center1)
+		r2 := c.Position.Sub(center2)
+
+		dv := a.Body2.Velocity.Add(CrossSV(a.Body2.AngularVelocity, r2))
+		dv = dv.Sub(a.Body1.Velocity)
+		dv = dv.Sub(CrossSV(a.Body1.AngularVelocity, r1))
+		c.RelativeVelocity = Dot(dv, c.Normal)
+	}
+}
+
 func (a *Arbiter) PreStep(invDt float64) {
 	var (
 		k_allowedPenetration = 0.01
@@ -144,9 +182,14 @@ func (a *Arbiter) PreStep(invDt float64) {
 		k_biasFactor = 0.2
 	}
 
+	center1 := a.Body1.WorldCenter()
+	center2 := a.Body2.WorldCenter()
+
 	for _, c := range a.Contacts {
-		r1 := c.Position.Sub(a.Body1.Position)
-		r2 := c.Position.Sub(a.Body2.Position)
+		r1 := c.Position.Sub(center1)
+		r2 := c.Position.Sub(center2)
+		c.R1 = r1
+		c.R2 = r2
 
 		// Precompute normal mass, tangent mass, and bias.
 		rn1 := Dot(r1, c.Normal)
@@ -161,7 +204,12 @@ func (a *Arbiter) PreStep(invDt float64) {
 		kTangent += a.Body1.invI*(Dot(r1, r1)-rt1*rt1) + a.Body2.invI*(Dot(r2, r2)-rt2*rt2)
 		c.MassTangent = 1.0 / kTangent
 
-		c.Bias = -k_biasFactor * invDt * math.Min(0.0, c.Separation+k_allowedPenetration)
+		if c.Separation > 0.0 {
+			// Speculative contact: the bodies may close the gap, no more.
+			c.Bias = -invDt * c.Separation
+		} else {
+			c.Bias = -k_biasFactor * invDt * math.Min(0.0, c.Separation+k_allowedPenetration)
+		}
 
 		if accumulateImpulses {
 			// Apply normal + friction impulse
@@ -183,9 +231,6 @@ func (a *Arbiter) ApplyImpulse() {
 	b2 := a.Body2
 
 	for _, c := range a.Contacts {
-		c.R1 = c.Position.Sub(b1.Position)
-		c.R2 = c.Position.Sub(b2.Position)
-
 		// Relative velocity at contact
 		// Vec2 dv = b2->velocity + Cross(b2->angularVelocity, c->r2) - b1->velocity - Cross(b1->angularVelocity, c->r1);
 		dv := b2.Velocity.Add(CrossSV(b2.AngularVelocity, c.R2))
@@ -195,7 +240,15 @@ func (a *Arbiter) ApplyImpulse() {
 		// Compute normal impulse
 		vn := Dot(dv, c.Normal)
 
-		dPn := c.MassNormal * (-vn + c.Bias)
+		// Bounce off with the restitution of the approach speed. A
+		// speculative contact only bounces if it closes within the step;
+		// without restitution it is left to close the gap.
+		bias := c.Bias
+		if vRel := c.RelativeVelocity; a.Restitution > 0.0 && vRel < -a.restitutionThreshold && vRel < c.Bias {
+			bias = math.Max(bias, -a.Restitution*vRel)
+		}
+
+		dPn := c.MassNormal * (-vn + bias)
 
 		if accumulateImpulses {
 			Pn0 := c.Pn
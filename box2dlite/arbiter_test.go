@@ -0,0 +1,48 @@
+package box2dlite
+
+import (
+	"math"
+	"testing"
+)
+
+// bounceHeight drops a ball of the given restitution from y = 5 onto the
+// ground and returns the highest it gets after the first bounce.
+func bounceHeight(w *World, restitution float64) float64 {
+	addBody(w, NewBox(Vec2{20.0, 1.0}), math.MaxFloat64, Vec2{0.0, -0.5})
+	ball := addBody(w, NewCircle(0.5), 1.0, Vec2{0.0, 5.0})
+	ball.Restitution = restitution
+
+	bounced := false
+	highest := 0.0
+	for i := 0; i < 180; i++ {
+		w.Step(1.0 / 60.0)
+		if ball.Velocity.Y > 0.0 {
+			bounced = true
+		}
+		if bounced {
+			highest = math.Max(highest, ball.Position.Y)
+		}
+	}
+	return highest
+}
+
+func TestRestitution(t *testing.T) {
+	if h := bounceHeight(NewWorld(Vec2{0.0, -10.0}, 10), 1.0); h < 4.0 {
+		t.Errorf("elastic ball bounced to y = %v, want near 5", h)
+	}
+	if h := bounceHeight(NewWorld(Vec2{0.0, -10.0}, 10), 0.5); h < 1.2 || h > 2.0 {
+		t.Errorf("half elastic ball bounced to y = %v, want about 1.6", h)
+	}
+	if h := bounceHeight(NewWorld(Vec2{0.0, -10.0}, 10), 0.0); h > 0.6 {
+		t.Errorf("inelastic ball bounced to y = %v, want it resting at 0.5", h)
+	}
+}
+
+func TestRestitutionThreshold(t *testing.T) {
+	// The ball hits the ground at about 9.5, under a threshold of 20.
+	w := NewWorld(Vec2{0.0, -10.0}, 10)
+	w.RestitutionThreshold = 20.0
+	if h := bounceHeight(w, 1.0); h > 0.6 {
+		t.Errorf("ball under the threshold bounced to y = %v, want it resting at 0.5", h)
+	}
+}
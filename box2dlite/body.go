@@ -14,6 +14,13 @@ type Body struct {
 	Force  Vec2
 	Torque float64
 
+	Fixtures []*Fixture
+
+	// Center of mass in the body frame
+	LocalCenter Vec2
+
+	// Size of the box given to Set, kept for code written before shapes.
+	// It is zero for bodies of other shapes.
 	Width Vec2
 
 	Friction float64
@@ -21,9 +28,29 @@ type Body struct {
 	invMass  float64
 	I        float64
 	invI     float64
+
+	// Share of the approach speed a body bounces back with, from 0 to 1.
+	// A contact uses the larger restitution of its two bodies.
+	Restitution float64
+
+	// Bullets are swept over each step so they cannot pass through
+	// thin bodies. They cost more, so mark only fast, small bodies.
+	Bullet bool
+
+	// Center of mass and rotation at the start of the step
+	center0   Vec2
+	rotation0 float64
 }
 
+// Set makes the body a box of size w with mass m.
+// Pass math.MaxFloat64 as the mass for a static body.
 func (b *Body) Set(w *Vec2, m float64) {
+	b.SetShape(NewBox(*w), m)
+}
+
+// SetShape resets the body to the single shape s with mass m.
+// Pass math.MaxFloat64 as the mass for a static body.
+func (b *Body) SetShape(s Shape, m float64) {
 	b.Position = Vec2{0.0, 0.0}
 	b.Rotation = 0.0
 	b.Velocity = Vec2{0.0, 0.0}
@@ -31,17 +58,132 @@ func (b *Body) Set(w *Vec2, m float64) {
 	b.Force = Vec2{0.0, 0.0}
 	b.Torque = 0.0
 	b.Friction = 0.2
+	b.Restitution = 0.0
+
+	b.Fixtures = nil
 
-	b.Width = *w
-	b.Mass = m
+	b.Width = Vec2{0.0, 0.0}
+	if box, ok := s.(*Box); ok {
+		b.Width = box.Width
+	}
 
+	var density float64
 	if m < math.MaxFloat64 {
-		b.invMass = 1.0 / m
-		b.I = m * (w.X*w.X + w.Y*w.Y) / 12.0
-		b.invI = 1.0 / b.I
+		if md := s.ComputeMass(1.0); md.Mass > 0.0 {
+			density = m / md.Mass
+		}
+	}
+
+	b.AddFixture(s, Vec2{0.0, 0.0}, 0.0, density)
+}
+
+// AddFixture attaches shape s with the given density to the body at the
+// given local position and angle, and updates the mass with ResetMass.
+func (b *Body) AddFixture(s Shape, position Vec2, angle float64, density float64) *Fixture {
+	f := &Fixture{
+		Shape:    s,
+		Position: position,
+		Rotation: angle,
+		Density:  density,
+		Filter:   DefaultFilter(),
+		Body:     b,
+	}
+	b.Fixtures = append(b.Fixtures, f)
+	b.ResetMass()
+	return f
+}
+
+// SetFilter sets the collision filter of all the body's fixtures.
+func (b *Body) SetFilter(filter Filter) {
+	for _, f := range b.Fixtures {
+		f.Filter = filter
+	}
+}
+
+// ResetMass computes the mass, center of mass and inertia from the
+// densities of the fixtures, dropping any SetMassData override.
+// A body without mass, such as one whose fixtures all have zero density
+// or no area, is static.
+func (b *Body) ResetMass() {
+	var md MassData
+
+	mds := make([]MassData, len(b.Fixtures))
+	for i, f := range b.Fixtures {
+		fmd := f.Shape.ComputeMass(f.Density)
+		xf := f.LocalTransform()
+		fmd.Center = xf.MulV(fmd.Center)
+		mds[i] = fmd
+
+		md.Mass += fmd.Mass
+		md.Center = md.Center.Add(MulSV(fmd.Mass, fmd.Center))
+	}
+
+	if md.Mass > 0.0 {
+		md.Center = MulSV(1.0/md.Mass, md.Center)
+
+		// Parallel axis theorem
+		for _, fmd := range mds {
+			d := fmd.Center.Sub(md.Center)
+			md.I += fmd.I + fmd.Mass*Dot(d, d)
+		}
+	}
+
+	b.SetMassData(md)
+}
+
+// SetMassData overrides the mass properties computed from the fixtures.
+// The inertia is about md.Center. A mass of zero or math.MaxFloat64 makes
+// the body static, and an inertia of zero or math.MaxFloat64 keeps it from
+// rotating.
+func (b *Body) SetMassData(md MassData) {
+	if md.Mass > 0.0 && md.Mass < math.MaxFloat64 {
+		b.Mass = md.Mass
+		b.invMass = 1.0 / md.Mass
+		b.LocalCenter = md.Center
 	} else {
+		b.Mass = math.MaxFloat64
 		b.invMass = 0.0
+		b.LocalCenter = Vec2{0.0, 0.0}
+	}
+
+	if b.invMass > 0.0 && md.I > 0.0 && md.I < math.MaxFloat64 {
+		b.I = md.I
+		b.invI = 1.0 / md.I
+	} else {
 		b.I = math.MaxFloat64
 		b.invI = 0.0
 	}
 }
+
+// MassData returns the mass properties of the body.
+func (b *Body) MassData() MassData {
+	return MassData{
+		Mass:   b.Mass,
+		Center: b.LocalCenter,
+		I:      b.I,
+	}
+}
+
+func (b *Body) Transform() Transform {
+	return NewTransform(b.Position, b.Rotation)
+}
+
+// WorldCenter returns the center of mass in world space.
+func (b *Body) WorldCenter() Vec2 {
+	xf := b.Transform()
+	return xf.MulV(b.LocalCenter)
+}
+
+// AABB returns the world bounding box of all the body's fixtures. A body
+// without fixtures has an empty box at its position.
+func (b *Body) AABB() AABB {
+	if len(b.Fixtures) == 0 {
+		return AABB{b.Position, b.Position}
+	}
+
+	aabb := b.Fixtures[0].AABB()
+	for _, f := range b.Fixtures[1:] {
+		aabb = aabb.Combine(f.AABB())
+	}
+	return aabb
+}
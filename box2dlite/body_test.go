@@ -0,0 +1,25 @@
+package box2dlite
+
+import (
+	"testing"
+)
+
+func TestBodyAABBWithoutFixtures(t *testing.T) {
+	b := &Body{Position: Vec2{1.0, 2.0}}
+
+	aabb := b.AABB()
+	if aabb.Min != b.Position || aabb.Max != b.Position {
+		t.Errorf("AABB() = %v, want an empty box at %v", aabb, b.Position)
+	}
+}
+
+func TestBodyAABBCombinesFixtures(t *testing.T) {
+	b := &Body{}
+	b.AddFixture(NewBox(Vec2{1.0, 1.0}), Vec2{-2.0, 0.0}, 0.0, 1.0)
+	b.AddFixture(NewCircle(0.5), Vec2{2.0, 1.0}, 0.0, 1.0)
+
+	want := AABB{Vec2{-2.5, -0.5}, Vec2{2.5, 1.5}}
+	if aabb := b.AABB(); aabb != want {
+		t.Errorf("AABB() = %v, want %v", aabb, want)
+	}
+}
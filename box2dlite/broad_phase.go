@@ -0,0 +1,315 @@
+package box2dlite
+
+// BroadPhase finds the pairs of fixtures that may touch, so the narrow-phase
+// only runs for those. Each fixture has a proxy holding a fat AABB, its
+// AABB grown by a margin, so small moves leave the proxy alone. The world
+// keeps a pair while the fat AABBs of its proxies overlap.
+//
+// Pick an implementation with World.SetBroadPhase:
+//   - NewTreeBroadPhase, the default, suits most scenes,
+//   - NewSweepAndPruneBroadPhase suits long levels spread along the x axis,
+//   - NewGridBroadPhase suits many shapes of similar size in a dense area,
+//   - NewBruteForceBroadPhase tests every pair, for small or debug scenes.
+type BroadPhase interface {
+	// CreateProxy adds a proxy for aabb and returns its id.
+	CreateProxy(aabb AABB, userData interface{}) int
+
+	// DestroyProxy removes a proxy. Its id may be reused.
+	DestroyProxy(id int)
+
+	// MoveProxy updates a proxy for its new aabb after a move by displacement.
+	MoveProxy(id int, aabb AABB, displacement Vec2)
+
+	// TouchProxy makes the next UpdatePairs report the pairs of a proxy again.
+	TouchProxy(id int)
+
+	// FatAABB returns the fat AABB of a proxy.
+	FatAABB(id int) AABB
+
+	// UserData returns the data the proxy was created with.
+	UserData(id int) interface{}
+
+	// UpdatePairs calls callback for each pair of proxies with overlapping
+	// fat AABBs where at least one of them was created, moved out of its fat
+	// AABB or touched since the last call. A pair may be reported twice.
+	UpdatePairs(callback func(userDataA, userDataB interface{}))
+
+	// Query calls callback for each proxy whose fat AABB overlaps aabb,
+	// until the callback returns false.
+	Query(aabb AABB, callback func(id int) bool)
+
+	// RayCast calls callback for each proxy whose fat AABB the ray may
+	// cross. The callback gets the ray cut at the current fraction and
+	// returns the fraction to cut it at from then on: 0 ends the cast, and a
+	// negative value leaves the ray as it is.
+	RayCast(input RayCastInput, callback func(input RayCastInput, id int) float64)
+}
+
+const (
+	// Margin of the fat AABBs
+	aabbExtension = 0.1
+
+	// Fattens the AABBs in the direction of motion
+	aabbMultiplier = 4.0
+)
+
+// fattenAABB grows aabb by the margin and stretches it along the predicted displacement.
+func fattenAABB(aabb AABB, displacement Vec2) AABB {
+	r := Vec2{aabbExtension, aabbExtension}
+	fatAABB := AABB{aabb.Min.Sub(r), aabb.Max.Add(r)}
+
+	// Predict the motion.
+	d := MulSV(aabbMultiplier, displacement)
+	if d.X < 0.0 {
+		fatAABB.Min.X += d.X
+	} else {
+		fatAABB.Max.X += d.X
+	}
+	if d.Y < 0.0 {
+		fatAABB.Min.Y += d.Y
+	} else {
+		fatAABB.Max.Y += d.Y
+	}
+
+	return fatAABB
+}
+
+// refattenAABB returns the new fat AABB of a proxy with the fat AABB
+// oldFat, now at aabb after a move by displacement. It reports false if the
+// proxy can keep oldFat.
+func refattenAABB(oldFat, aabb AABB, displacement Vec2) (AABB, bool) {
+	fatAABB := fattenAABB(aabb, displacement)
+
+	if oldFat.Contains(aabb) {
+		// The fat AABB still holds the object, but it might be too large.
+		// Replace it if it is larger than a huge AABB.
+		r := Vec2{4.0 * aabbExtension, 4.0 * aabbExtension}
+		hugeAABB := AABB{fatAABB.Min.Sub(r), fatAABB.Max.Add(r)}
+		if hugeAABB.Contains(oldFat) {
+			return oldFat, false
+		}
+	}
+
+	return fatAABB, true
+}
+
+// rayCastQuery ray casts the proxies of bp through its Query, for the
+// broad-phases without a faster way.
+func rayCastQuery(bp BroadPhase, input RayCastInput, callback func(input RayCastInput, id int) float64) {
+	maxFraction := input.MaxFraction
+
+	bp.Query(segmentAABB(input.P1, input.P2, maxFraction), func(id int) bool {
+		if !segmentOverlapsAABB(input.P1, input.P2, maxFraction, bp.FatAABB(id)) {
+			return true
+		}
+
+		value := callback(RayCastInput{input.P1, input.P2, maxFraction}, id)
+		if value == 0.0 {
+			return false
+		}
+		if value > 0.0 {
+			maxFraction = value
+		}
+		return true
+	})
+}
+
+// TreeBroadPhase keeps the proxies in a DynamicTree and queries it for the
+// proxies that moved.
+type TreeBroadPhase struct {
+	tree       *DynamicTree
+	moveBuffer []int
+}
+
+func NewTreeBroadPhase() *TreeBroadPhase {
+	return &TreeBroadPhase{
+		tree: NewDynamicTree(),
+	}
+}
+
+func (bp *TreeBroadPhase) CreateProxy(aabb AABB, userData interface{}) int {
+	id := bp.tree.CreateProxy(aabb, userData)
+	bp.moveBuffer = append(bp.moveBuffer, id)
+	return id
+}
+
+func (bp *TreeBroadPhase) DestroyProxy(id int) {
+	for i, moved := range bp.moveBuffer {
+		if moved == id {
+			bp.moveBuffer[i] = nullNode
+		}
+	}
+	bp.tree.DestroyProxy(id)
+}
+
+func (bp *TreeBroadPhase) MoveProxy(id int, aabb AABB, displacement Vec2) {
+	if bp.tree.MoveProxy(id, aabb, displacement) {
+		bp.moveBuffer = append(bp.moveBuffer, id)
+	}
+}
+
+func (bp *TreeBroadPhase) TouchProxy(id int) {
+	bp.moveBuffer = append(bp.moveBuffer, id)
+}
+
+func (bp *TreeBroadPhase) FatAABB(id int) AABB {
+	return bp.tree.FatAABB(id)
+}
+
+func (bp *TreeBroadPhase) UserData(id int) interface{} {
+	return bp.tree.UserData(id)
+}
+
+func (bp *TreeBroadPhase) UpdatePairs(callback func(userDataA, userDataB interface{})) {
+	for _, id := range bp.moveBuffer {
+		if id == nullNode {
+			continue
+		}
+
+		userData := bp.tree.UserData(id)
+		bp.tree.Query(bp.tree.FatAABB(id), func(other int) bool {
+			if other != id {
+				callback(userData, bp.tree.UserData(other))
+			}
+			return true
+		})
+	}
+	bp.moveBuffer = bp.moveBuffer[:0]
+}
+
+func (bp *TreeBroadPhase) Query(aabb AABB, callback func(id int) bool) {
+	bp.tree.Query(aabb, callback)
+}
+
+func (bp *TreeBroadPhase) RayCast(input RayCastInput, callback func(input RayCastInput, id int) float64) {
+	bp.tree.RayCast(input, callback)
+}
+
+// proxy is an entry of a proxyPool.
+type proxy struct {
+	aabb     AABB
+	userData interface{}
+	moved    bool
+	alive    bool
+}
+
+// proxyPool stores the proxies of the flat broad-phases and recycles their ids.
+type proxyPool struct {
+	proxies []proxy
+	free    []int
+}
+
+func (p *proxyPool) create(aabb AABB, userData interface{}) int {
+	pr := proxy{
+		aabb:     fattenAABB(aabb, Vec2{0.0, 0.0}),
+		userData: userData,
+		moved:    true,
+		alive:    true,
+	}
+
+	if n := len(p.free); n > 0 {
+		id := p.free[n-1]
+		p.free = p.free[:n-1]
+		p.proxies[id] = pr
+		return id
+	}
+
+	p.proxies = append(p.proxies, pr)
+	return len(p.proxies) - 1
+}
+
+func (p *proxyPool) destroy(id int) {
+	p.proxies[id] = proxy{}
+	p.free = append(p.free, id)
+}
+
+// move refattens a proxy if needed and reports whether it did.
+func (p *proxyPool) move(id int, aabb AABB, displacement Vec2) bool {
+	pr := &p.proxies[id]
+	fatAABB, ok := refattenAABB(pr.aabb, aabb, displacement)
+	if ok {
+		pr.aabb = fatAABB
+		pr.moved = true
+	}
+	return ok
+}
+
+// BruteForceBroadPhase tests each moved proxy against all the others.
+type BruteForceBroadPhase struct {
+	pool proxyPool
+}
+
+func NewBruteForceBroadPhase() *BruteForceBroadPhase {
+	return &BruteForceBroadPhase{}
+}
+
+func (bp *BruteForceBroadPhase) CreateProxy(aabb AABB, userData interface{}) int {
+	return bp.pool.create(aabb, userData)
+}
+
+func (bp *BruteForceBroadPhase) DestroyProxy(id int) {
+	bp.pool.destroy(id)
+}
+
+func (bp *BruteForceBroadPhase) MoveProxy(id int, aabb AABB, displacement Vec2) {
+	bp.pool.move(id, aabb, displacement)
+}
+
+func (bp *BruteForceBroadPhase) TouchProxy(id int) {
+	bp.pool.proxies[id].moved = true
+}
+
+func (bp *BruteForceBroadPhase) FatAABB(id int) AABB {
+	return bp.pool.proxies[id].aabb
+}
+
+func (bp *BruteForceBroadPhase) UserData(id int) interface{} {
+	return bp.pool.proxies[id].userData
+}
+
+func (bp *BruteForceBroadPhase) UpdatePairs(callback func(userDataA, userDataB interface{})) {
+	proxies := bp.pool.proxies
+
+	// O(n^2) broad-phase
+	for i := range proxies {
+		pi := &proxies[i]
+		if !pi.alive || !pi.moved {
+			continue
+		}
+
+		for j := range proxies {
+			pj := &proxies[j]
+			if j == i || !pj.alive {
+				continue
+			}
+
+			// Both proxies moved: report the pair once.
+			if pj.moved && j < i {
+				continue
+			}
+
+			if pi.aabb.Overlaps(pj.aabb) {
+				callback(pi.userData, pj.userData)
+			}
+		}
+	}
+
+	for i := range proxies {
+		proxies[i].moved = false
+	}
+}
+
+func (bp *BruteForceBroadPhase) Query(aabb AABB, callback func(id int) bool) {
+	for id := range bp.pool.proxies {
+		pr := &bp.pool.proxies[id]
+		if pr.alive && pr.aabb.Overlaps(aabb) {
+			if !callback(id) {
+				return
+			}
+		}
+	}
+}
+
+func (bp *BruteForceBroadPhase) RayCast(input RayCastInput, callback func(input RayCastInput, id int) float64) {
+	rayCastQuery(bp, input, callback)
+}
@@ -0,0 +1,225 @@
+package box2dlite
+
+import (
+	"math"
+)
+
+// GridBroadPhase hashes the proxies into the square cells of a uniform
+// grid they overlap, so only proxies sharing a cell are tested. It suits
+// many shapes about the size of a cell packed in an area. A proxy much
+// larger than a cell, such as long static ground, is listed in every cell
+// it covers, so keep those few. A proxy covering more than maxGridCells
+// cells is kept out of the grid and tested by every query instead.
+type GridBroadPhase struct {
+	pool     proxyPool
+	cellSize float64
+	cells    map[gridCell][]int
+
+	// ids of the proxies too large for the grid
+	large []int
+
+	// marks[id] == mark once query has visited proxy id
+	marks []int
+	mark  int
+}
+
+// maxGridCells bounds the cells a proxy is listed in or a query visits.
+const maxGridCells = 1024
+
+// gridCell is the coordinate of a grid cell.
+type gridCell struct {
+	x, y int
+}
+
+// NewGridBroadPhase makes a grid of cells cellSize wide. It panics if cellSize is not positive.
+func NewGridBroadPhase(cellSize float64) *GridBroadPhase {
+	if cellSize <= 0.0 {
+		panic("NewGridBroadPhase fails because the cell size is not positive")
+	}
+	return &GridBroadPhase{
+		cellSize: cellSize,
+		cells:    make(map[gridCell][]int),
+	}
+}
+
+// cellRange returns the lowest and highest cells overlapping aabb. It
+// reports false if aabb is not finite or covers more than maxGridCells cells.
+func (bp *GridBroadPhase) cellRange(aabb AABB) (gridCell, gridCell, bool) {
+	minX := math.Floor(aabb.Min.X / bp.cellSize)
+	minY := math.Floor(aabb.Min.Y / bp.cellSize)
+	maxX := math.Floor(aabb.Max.X / bp.cellSize)
+	maxY := math.Floor(aabb.Max.Y / bp.cellSize)
+
+	// Also false for NaN, from infinite bounds.
+	if !((maxX-minX+1.0)*(maxY-minY+1.0) <= maxGridCells) {
+		return gridCell{}, gridCell{}, false
+	}
+
+	lower := gridCell{int(minX), int(minY)}
+	upper := gridCell{int(maxX), int(maxY)}
+	return lower, upper, true
+}
+
+func (bp *GridBroadPhase) insert(id int) {
+	lower, upper, ok := bp.cellRange(bp.pool.proxies[id].aabb)
+	if !ok {
+		bp.large = append(bp.large, id)
+		return
+	}
+
+	for x := lower.x; x <= upper.x; x++ {
+		for y := lower.y; y <= upper.y; y++ {
+			c := gridCell{x, y}
+			bp.cells[c] = append(bp.cells[c], id)
+		}
+	}
+}
+
+func (bp *GridBroadPhase) remove(id int) {
+	lower, upper, ok := bp.cellRange(bp.pool.proxies[id].aabb)
+	if !ok {
+		for i, o := range bp.large {
+			if o == id {
+				bp.large[i] = bp.large[len(bp.large)-1]
+				bp.large = bp.large[:len(bp.large)-1]
+				break
+			}
+		}
+		return
+	}
+
+	for x := lower.x; x <= upper.x; x++ {
+		for y := lower.y; y <= upper.y; y++ {
+			c := gridCell{x, y}
+			ids := bp.cells[c]
+			for i, o := range ids {
+				if o == id {
+					ids[i] = ids[len(ids)-1]
+					ids = ids[:len(ids)-1]
+					break
+				}
+			}
+			if len(ids) == 0 {
+				delete(bp.cells, c)
+			} else {
+				bp.cells[c] = ids
+			}
+		}
+	}
+}
+
+func (bp *GridBroadPhase) CreateProxy(aabb AABB, userData interface{}) int {
+	id := bp.pool.create(aabb, userData)
+	bp.insert(id)
+	return id
+}
+
+func (bp *GridBroadPhase) DestroyProxy(id int) {
+	bp.remove(id)
+	bp.pool.destroy(id)
+}
+
+func (bp *GridBroadPhase) MoveProxy(id int, aabb AABB, displacement Vec2) {
+	fatAABB, ok := refattenAABB(bp.pool.proxies[id].aabb, aabb, displacement)
+	if !ok {
+		return
+	}
+
+	bp.remove(id)
+	bp.pool.proxies[id].aabb = fatAABB
+	bp.pool.proxies[id].moved = true
+	bp.insert(id)
+}
+
+func (bp *GridBroadPhase) TouchProxy(id int) {
+	bp.pool.proxies[id].moved = true
+}
+
+func (bp *GridBroadPhase) FatAABB(id int) AABB {
+	return bp.pool.proxies[id].aabb
+}
+
+func (bp *GridBroadPhase) UserData(id int) interface{} {
+	return bp.pool.proxies[id].userData
+}
+
+func (bp *GridBroadPhase) UpdatePairs(callback func(userDataA, userDataB interface{})) {
+	proxies := bp.pool.proxies
+
+	for id := range proxies {
+		pr := &proxies[id]
+		if !pr.alive || !pr.moved {
+			continue
+		}
+
+		bp.query(pr.aabb, func(other int) bool {
+			po := &proxies[other]
+			// Both proxies moved: report the pair once.
+			if other != id && !(po.moved && other < id) {
+				callback(pr.userData, po.userData)
+			}
+			return true
+		})
+	}
+
+	for id := range proxies {
+		proxies[id].moved = false
+	}
+}
+
+func (bp *GridBroadPhase) Query(aabb AABB, callback func(id int) bool) {
+	bp.query(aabb, callback)
+}
+
+func (bp *GridBroadPhase) RayCast(input RayCastInput, callback func(input RayCastInput, id int) float64) {
+	rayCastQuery(bp, input, callback)
+}
+
+// query calls callback once for each proxy overlapping aabb in the cells it
+// covers or in the large list. If aabb covers too many cells, it tests all
+// the proxies instead. The marks make it not reentrant.
+func (bp *GridBroadPhase) query(aabb AABB, callback func(id int) bool) {
+	proxies := bp.pool.proxies
+
+	lower, upper, ok := bp.cellRange(aabb)
+	if !ok {
+		for id := range proxies {
+			if proxies[id].alive && proxies[id].aabb.Overlaps(aabb) {
+				if !callback(id) {
+					return
+				}
+			}
+		}
+		return
+	}
+
+	for len(bp.marks) < len(proxies) {
+		bp.marks = append(bp.marks, 0)
+	}
+	bp.mark++
+
+	for x := lower.x; x <= upper.x; x++ {
+		for y := lower.y; y <= upper.y; y++ {
+			for _, id := range bp.cells[gridCell{x, y}] {
+				if bp.marks[id] == bp.mark {
+					continue
+				}
+				bp.marks[id] = bp.mark
+
+				if proxies[id].aabb.Overlaps(aabb) {
+					if !callback(id) {
+						return
+					}
+				}
+			}
+		}
+	}
+
+	for _, id := range bp.large {
+		if proxies[id].aabb.Overlaps(aabb) {
+			if !callback(id) {
+				return
+			}
+		}
+	}
+}
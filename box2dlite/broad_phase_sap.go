@@ -0,0 +1,126 @@
+package box2dlite
+
+// SweepAndPruneBroadPhase keeps the proxies sorted by the left side of
+// their fat AABBs and sweeps along the x axis for overlaps. The order
+// barely changes from step to step, so an insertion sort keeps it cheap.
+// It suits scenes spread along the x axis, such as side-scrolling levels.
+type SweepAndPruneBroadPhase struct {
+	pool proxyPool
+
+	// ids of the live proxies by increasing Min.X
+	order []int
+
+	// Proxies were created or moved since the last sort.
+	unsorted bool
+}
+
+func NewSweepAndPruneBroadPhase() *SweepAndPruneBroadPhase {
+	return &SweepAndPruneBroadPhase{}
+}
+
+func (bp *SweepAndPruneBroadPhase) CreateProxy(aabb AABB, userData interface{}) int {
+	id := bp.pool.create(aabb, userData)
+	bp.order = append(bp.order, id)
+	bp.unsorted = true
+	return id
+}
+
+func (bp *SweepAndPruneBroadPhase) DestroyProxy(id int) {
+	for i, o := range bp.order {
+		if o == id {
+			bp.order = append(bp.order[:i], bp.order[i+1:]...)
+			break
+		}
+	}
+	bp.pool.destroy(id)
+}
+
+func (bp *SweepAndPruneBroadPhase) MoveProxy(id int, aabb AABB, displacement Vec2) {
+	if bp.pool.move(id, aabb, displacement) {
+		bp.unsorted = true
+	}
+}
+
+func (bp *SweepAndPruneBroadPhase) TouchProxy(id int) {
+	bp.pool.proxies[id].moved = true
+}
+
+func (bp *SweepAndPruneBroadPhase) FatAABB(id int) AABB {
+	return bp.pool.proxies[id].aabb
+}
+
+func (bp *SweepAndPruneBroadPhase) UserData(id int) interface{} {
+	return bp.pool.proxies[id].userData
+}
+
+func (bp *SweepAndPruneBroadPhase) UpdatePairs(callback func(userDataA, userDataB interface{})) {
+	proxies := bp.pool.proxies
+	bp.sort()
+
+	for i, id := range bp.order {
+		pi := &proxies[id]
+		for _, other := range bp.order[i+1:] {
+			pj := &proxies[other]
+
+			// The rest of the proxies start right of this one.
+			if pj.aabb.Min.X > pi.aabb.Max.X {
+				break
+			}
+
+			if !pi.moved && !pj.moved {
+				continue
+			}
+
+			if pi.aabb.Min.Y <= pj.aabb.Max.Y && pj.aabb.Min.Y <= pi.aabb.Max.Y {
+				callback(pi.userData, pj.userData)
+			}
+		}
+	}
+
+	for _, id := range bp.order {
+		proxies[id].moved = false
+	}
+}
+
+func (bp *SweepAndPruneBroadPhase) Query(aabb AABB, callback func(id int) bool) {
+	bp.sort()
+
+	for _, id := range bp.order {
+		pr := &bp.pool.proxies[id]
+		if pr.aabb.Min.X > aabb.Max.X {
+			return
+		}
+
+		if pr.aabb.Overlaps(aabb) {
+			if !callback(id) {
+				return
+			}
+		}
+	}
+}
+
+func (bp *SweepAndPruneBroadPhase) RayCast(input RayCastInput, callback func(input RayCastInput, id int) float64) {
+	rayCastQuery(bp, input, callback)
+}
+
+// sort restores the order after proxies moved, with an insertion sort.
+func (bp *SweepAndPruneBroadPhase) sort() {
+	if !bp.unsorted {
+		return
+	}
+	bp.unsorted = false
+
+	proxies := bp.pool.proxies
+	order := bp.order
+
+	for i := 1; i < len(order); i++ {
+		id := order[i]
+		x := proxies[id].aabb.Min.X
+
+		j := i
+		for ; j > 0 && proxies[order[j-1]].aabb.Min.X > x; j-- {
+			order[j] = order[j-1]
+		}
+		order[j] = id
+	}
+}
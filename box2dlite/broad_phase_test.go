@@ -0,0 +1,152 @@
+package box2dlite
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// pairKey is an unordered pair of proxy user data.
+type pairKey struct {
+	a, b int
+}
+
+func newPairKey(a, b int) pairKey {
+	if a > b {
+		a, b = b, a
+	}
+	return pairKey{a, b}
+}
+
+func updatePairSet(bp BroadPhase) map[pairKey]bool {
+	pairs := make(map[pairKey]bool)
+	bp.UpdatePairs(func(userDataA, userDataB interface{}) {
+		pairs[newPairKey(userDataA.(int), userDataB.(int))] = true
+	})
+	return pairs
+}
+
+func querySet(bp BroadPhase, aabb AABB) map[int]bool {
+	found := make(map[int]bool)
+	bp.Query(aabb, func(id int) bool {
+		found[bp.UserData(id).(int)] = true
+		return true
+	})
+	return found
+}
+
+func samePairs(a, b map[pairKey]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if !b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameSet(a, b map[int]bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if !b[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// TestBroadPhasesMatchBruteForce runs the same proxy operations on every
+// broad-phase and checks they report the pairs and query results of the
+// brute-force one.
+func TestBroadPhasesMatchBruteForce(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		bp   BroadPhase
+	}{
+		{"tree", NewTreeBroadPhase()},
+		{"sap", NewSweepAndPruneBroadPhase()},
+		{"grid", NewGridBroadPhase(2.0)},
+	} {
+		r := rand.New(rand.NewSource(1))
+		ref := NewBruteForceBroadPhase()
+
+		// Tight AABBs and ids in both broad-phases, by user data
+		aabbs := make(map[int]AABB)
+		ids := make(map[int][2]int)
+		var live []int
+
+		for round := 0; round < 50; round++ {
+			for i := 0; i < 20; i++ {
+				switch op := r.Intn(10); {
+				case op < 3 || len(live) == 0:
+					ud := round*100 + i
+					aabb := randomAABB(r)
+					if r.Intn(20) == 0 {
+						// Long ground, too large for the grid
+						aabb = AABB{Vec2{-1000.0, aabb.Min.Y}, Vec2{1000.0, aabb.Max.Y}}
+					}
+					aabbs[ud] = aabb
+					ids[ud] = [2]int{ref.CreateProxy(aabb, ud), tc.bp.CreateProxy(aabb, ud)}
+					live = append(live, ud)
+
+				case op < 9:
+					ud := live[r.Intn(len(live))]
+					d := Vec2{r.Float64()*2.0 - 1.0, r.Float64()*2.0 - 1.0}
+					aabb := aabbs[ud]
+					aabb = AABB{aabb.Min.Add(d), aabb.Max.Add(d)}
+					aabbs[ud] = aabb
+					ref.MoveProxy(ids[ud][0], aabb, d)
+					tc.bp.MoveProxy(ids[ud][1], aabb, d)
+
+				default:
+					k := r.Intn(len(live))
+					ud := live[k]
+					ref.DestroyProxy(ids[ud][0])
+					tc.bp.DestroyProxy(ids[ud][1])
+					live[k] = live[len(live)-1]
+					live = live[:len(live)-1]
+					delete(aabbs, ud)
+					delete(ids, ud)
+				}
+			}
+
+			want := updatePairSet(ref)
+			if got := updatePairSet(tc.bp); !samePairs(got, want) {
+				t.Fatalf("%s: round %d: UpdatePairs found %d pairs, want %d", tc.name, round, len(got), len(want))
+			}
+
+			q := randomAABB(r)
+			if got, want := querySet(tc.bp, q), querySet(ref, q); !sameSet(got, want) {
+				t.Fatalf("%s: round %d: Query found %v, want %v", tc.name, round, got, want)
+			}
+		}
+	}
+}
+
+func TestGridBroadPhaseHugeAABBs(t *testing.T) {
+	bp := NewGridBroadPhase(0.5)
+
+	bp.CreateProxy(AABB{Vec2{-1.0e9, -1.0}, Vec2{1.0e9, 0.0}}, 0)
+	bp.CreateProxy(AABB{Vec2{0.0, 0.0}, Vec2{1.0, 1.0}}, 1)
+	bp.CreateProxy(AABB{Vec2{5.0e8, -0.5}, Vec2{5.0e8 + 1.0, 0.5}}, 2)
+
+	want := map[pairKey]bool{newPairKey(0, 1): true, newPairKey(0, 2): true}
+	if got := updatePairSet(bp); !samePairs(got, want) {
+		t.Errorf("UpdatePairs found %v, want %v", got, want)
+	}
+
+	inf := math.Inf(1)
+	all := AABB{Vec2{-inf, -inf}, Vec2{inf, inf}}
+	if got := querySet(bp, all); len(got) != 3 {
+		t.Errorf("Query of everything found %v, want 3 proxies", got)
+	}
+
+	near := AABB{Vec2{0.2, 0.2}, Vec2{0.3, 0.3}}
+	if got := querySet(bp, near); !sameSet(got, map[int]bool{1: true}) {
+		t.Errorf("Query near the box found %v, want only 1", got)
+	}
+}
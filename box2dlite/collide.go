@@ -123,17 +123,236 @@ func ComputeIncidentEdge(c []ClipVertex, h Vec2, pos Vec2, rot Mat22, normal Vec
 	c[1].v = pos.Add(rot.MulV(c[1].v))
 }
 
+// CollideFunc returns the contacts between shapeA placed at xfA and shapeB
+// placed at xfB, or nil when they do not touch.
+//
+// Contacts must follow the contract of the built-in functions:
+//   - the normal is a unit vector and points from A to B,
+//   - the separation is negative when the shapes overlap,
+//   - the position is a world point between the two surfaces,
+//   - the feature pair tells the contact apart from the other contacts of
+//     the same shape pair across steps, so impulses can be warm started.
+//     InEdge1 and OutEdge1 name the feature of A, InEdge2 and OutEdge2 the
+//     feature of B; NO_EDGE (0) means none. A function returning a single
+//     contact may leave it all zero.
+//
+// Only Position, Normal, Separation and Feature are read; the solver fills
+// in the rest.
+//
+// Registered functions get the shapes as they are: a World with Speculative
+// set only grows shapes for the built-in functions, so pairs collided by
+// registered functions get no contacts before they touch.
+type CollideFunc func(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact
+
+type shapePair struct {
+	a, b ShapeType
+}
+
+// Narrow-phase functions by shape pair. A pair only needs to be registered
+// in one order; collideShapes swaps the arguments for the other one.
+var collideFuncs map[shapePair]CollideFunc
+
+// Pairs whose function came from RegisterCollideFunc
+var customCollideFuncs = make(map[shapePair]bool)
+
+func init() {
+	// Set up in init because chains and heightfields collide through collideShapes.
+	collideFuncs = map[shapePair]CollideFunc{
+		{SHAPE_BOX, SHAPE_BOX}:             collideBoxes,
+		{SHAPE_CIRCLE, SHAPE_CIRCLE}:       collideCircles,
+		{SHAPE_BOX, SHAPE_CIRCLE}:          collideBoxCircle,
+		{SHAPE_POLYGON, SHAPE_POLYGON}:     collidePolygons,
+		{SHAPE_BOX, SHAPE_POLYGON}:         collideBoxPolygon,
+		{SHAPE_POLYGON, SHAPE_CIRCLE}:      collidePolygonCircle,
+		{SHAPE_CAPSULE, SHAPE_CAPSULE}:     collideCapsules,
+		{SHAPE_CAPSULE, SHAPE_CIRCLE}:      collideCapsuleCircle,
+		{SHAPE_BOX, SHAPE_CAPSULE}:         collideBoxCapsule,
+		{SHAPE_POLYGON, SHAPE_CAPSULE}:     collidePolygonCapsule,
+		{SHAPE_EDGE, SHAPE_BOX}:            collideEdgeBox,
+		{SHAPE_EDGE, SHAPE_POLYGON}:        collideEdgePolygon,
+		{SHAPE_EDGE, SHAPE_CAPSULE}:        collideEdgeCapsule,
+		{SHAPE_EDGE, SHAPE_CIRCLE}:         collideEdgeCircle,
+		{SHAPE_CHAIN, SHAPE_BOX}:           collideChain,
+		{SHAPE_CHAIN, SHAPE_POLYGON}:       collideChain,
+		{SHAPE_CHAIN, SHAPE_CAPSULE}:       collideChain,
+		{SHAPE_CHAIN, SHAPE_CIRCLE}:        collideChain,
+		{SHAPE_HEIGHTFIELD, SHAPE_BOX}:     collideHeightfield,
+		{SHAPE_HEIGHTFIELD, SHAPE_POLYGON}: collideHeightfield,
+		{SHAPE_HEIGHTFIELD, SHAPE_CAPSULE}: collideHeightfield,
+		{SHAPE_HEIGHTFIELD, SHAPE_CIRCLE}:  collideHeightfield,
+	}
+}
+
+// RegisterCollideFunc sets the narrow-phase function for shapes of typeA
+// and typeB, replacing any previous one. The pair is also used in the
+// other order, with the arguments swapped and the contacts flipped, unless
+// that order has a function of its own. A nil fn removes the pair.
+//
+// Custom shapes take types from SHAPE_USER up and implement all of Shape.
+// Register their functions before stepping any world; the registry is not
+// safe for concurrent use.
+func RegisterCollideFunc(typeA, typeB ShapeType, fn CollideFunc) {
+	pair := shapePair{typeA, typeB}
+	if fn == nil {
+		delete(collideFuncs, pair)
+		delete(customCollideFuncs, pair)
+		return
+	}
+	collideFuncs[pair] = fn
+	customCollideFuncs[pair] = true
+}
+
+// builtinCollide reports whether shapes of typeA and typeB collide through
+// a built-in function, including the edges of chains and heightfields.
+func builtinCollide(typeA, typeB ShapeType) bool {
+	pair := shapePair{typeA, typeB}
+	if _, ok := collideFuncs[pair]; !ok {
+		pair = shapePair{typeB, typeA}
+		if _, ok := collideFuncs[pair]; !ok {
+			return false
+		}
+	}
+	if customCollideFuncs[pair] {
+		return false
+	}
+
+	switch {
+	case typeA == SHAPE_CHAIN || typeA == SHAPE_HEIGHTFIELD:
+		return builtinCollide(SHAPE_EDGE, typeB)
+	case typeB == SHAPE_CHAIN || typeB == SHAPE_HEIGHTFIELD:
+		return builtinCollide(typeA, SHAPE_EDGE)
+	}
+	return true
+}
+
+// The normal points from A to B
+func collideShapes(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	if fn, ok := collideFuncs[shapePair{shapeA.Type(), shapeB.Type()}]; ok {
+		return fn(shapeA, xfA, shapeB, xfB)
+	}
+
+	if fn, ok := collideFuncs[shapePair{shapeB.Type(), shapeA.Type()}]; ok {
+		contacts := fn(shapeB, xfB, shapeA, xfA)
+		for _, c := range contacts {
+			c.Normal = c.Normal.Negative()
+			c.Feature.Flip()
+		}
+		return contacts
+	}
+
+	return nil
+}
+
+// Collide returns the contacts between all the fixtures of two bodies.
+// Feature ids are only unique per fixture pair, see CollideFixtures.
+//
 // The normal points from A to B
 func Collide(bodyA, bodyB *Body) []*Contact {
+	var contacts []*Contact
+	for _, fA := range bodyA.Fixtures {
+		for _, fB := range bodyB.Fixtures {
+			contacts = append(contacts, CollideFixtures(fA, fB)...)
+		}
+	}
+	return contacts
+}
+
+// CollideFixtures returns the contacts of the shapes of two fixtures where
+// their bodies are, see CollideShapes.
+//
+// The normal points from A to B
+func CollideFixtures(fixtureA, fixtureB *Fixture) []*Contact {
+	return collideShapes(fixtureA.Shape, fixtureA.Transform(), fixtureB.Shape, fixtureB.Transform())
+}
+
+// collideShapesSpeculative is collideShapes with one of the shapes grown by
+// margin, so it also returns contacts between shapes up to margin apart,
+// with a positive separation. Only shapes with a radius can grow, and only
+// for built-in functions: other pairs get the touching contacts alone.
+//
+// The normal points from A to B
+func collideShapesSpeculative(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform, margin float64) []*Contact {
+	if margin <= 0.0 || !builtinCollide(shapeA.Type(), shapeB.Type()) {
+		return collideShapes(shapeA, xfA, shapeB, xfB)
+	}
+
+	var contacts []*Contact
+	inflatedB := false
+	if s, ok := inflateShape(shapeB, margin); ok {
+		contacts = collideShapes(shapeA, xfA, s, xfB)
+		inflatedB = true
+	} else if s, ok := inflateShape(shapeA, margin); ok {
+		contacts = collideShapes(s, xfA, shapeB, xfB)
+	} else {
+		return collideShapes(shapeA, xfA, shapeB, xfB)
+	}
+
+	// Functions place the points anywhere between the surfaces they saw.
+	// Move each point along the normal to halfway between the real
+	// surfaces: the grown shape's real surface is its support plane along
+	// the normal, and the other lies the separation away.
+	for _, c := range contacts {
+		c.Separation += margin
+
+		var mid float64
+		if inflatedB {
+			d := xfB.R.MulTV(c.Normal.Negative())
+			p := shapeB.Support(d)
+			p = xfB.MulV(p)
+			mid = Dot(c.Normal, p) - 0.5*c.Separation
+		} else {
+			d := xfA.R.MulTV(c.Normal)
+			p := shapeA.Support(d)
+			p = xfA.MulV(p)
+			mid = Dot(c.Normal, p) + 0.5*c.Separation
+		}
+		c.Position = c.Position.Add(MulSV(mid-Dot(c.Normal, c.Position), c.Normal))
+	}
+	return contacts
+}
+
+// inflateShape returns a copy of s with its radius grown by margin, if s has one.
+func inflateShape(s Shape, margin float64) (Shape, bool) {
+	switch s := s.(type) {
+	case *Box:
+		inflated := *s
+		inflated.Radius += margin
+		return &inflated, true
+	case *Polygon:
+		inflated := *s
+		inflated.Radius += margin
+		return &inflated, true
+	case *Circle:
+		inflated := *s
+		inflated.Radius += margin
+		return &inflated, true
+	case *Capsule:
+		inflated := *s
+		inflated.Radius += margin
+		return &inflated, true
+	}
+	return nil, false
+}
+
+// The normal points from A to B
+func collideBoxes(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	boxA := shapeA.(*Box)
+	boxB := shapeB.(*Box)
+
+	// Rounded boxes go through the general polygon code.
+	if boxA.Radius > 0.0 || boxB.Radius > 0.0 {
+		return collideConvex(boxPolygon(boxA), xfA, boxPolygon(boxB), xfB)
+	}
+
 	// Setup
-	hA := MulSV(0.5, bodyA.Width)
-	hB := MulSV(0.5, bodyB.Width)
+	hA := MulSV(0.5, boxA.Width)
+	hB := MulSV(0.5, boxB.Width)
 
-	posA := bodyA.Position
-	posB := bodyB.Position
+	posA := xfA.Position
+	posB := xfB.Position
 
-	RotA := Mat22ByAngle(bodyA.Rotation)
-	RotB := Mat22ByAngle(bodyB.Rotation)
+	RotA := xfA.R
+	RotB := xfB.R
 
 	RotAT := RotA.Transpose()
 	RotBT := RotB.Transpose()
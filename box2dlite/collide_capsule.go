@@ -0,0 +1,182 @@
+package box2dlite
+
+import (
+	"math"
+)
+
+// segmentDistance finds the closest points c1 on segment p1-q1 and c2 on
+// segment p2-q2, along with their fractions along each segment.
+func segmentDistance(p1, q1, p2, q2 Vec2) (c1, c2 Vec2, f1, f2 float64) {
+	d1 := q1.Sub(p1)
+	d2 := q2.Sub(p2)
+	r := p1.Sub(p2)
+	dd1 := Dot(d1, d1)
+	dd2 := Dot(d2, d2)
+	rd1 := Dot(r, d1)
+	rd2 := Dot(r, d2)
+
+	const epsSqr = 1.0e-12
+
+	switch {
+	case dd1 < epsSqr && dd2 < epsSqr:
+		// Both segments are points.
+	case dd1 < epsSqr:
+		f2 = Clamp(rd2/dd2, 0.0, 1.0)
+	case dd2 < epsSqr:
+		f1 = Clamp(-rd1/dd1, 0.0, 1.0)
+	default:
+		d12 := Dot(d1, d2)
+		denom := dd1*dd2 - d12*d12
+
+		// Fraction on segment 1, or its start when the segments are parallel.
+		if denom != 0.0 {
+			f1 = Clamp((d12*rd2-rd1*dd2)/denom, 0.0, 1.0)
+		}
+
+		// Point on segment 2 closest to the one on segment 1.
+		f2 = (d12*f1 + rd2) / dd2
+
+		// Clamping segment 2 moves the closest point on segment 1.
+		if f2 < 0.0 {
+			f2 = 0.0
+			f1 = Clamp(-rd1/dd1, 0.0, 1.0)
+		} else if f2 > 1.0 {
+			f2 = 1.0
+			f1 = Clamp((d12-rd1)/dd1, 0.0, 1.0)
+		}
+	}
+
+	c1 = p1.Add(MulSV(f1, d1))
+	c2 = p2.Add(MulSV(f2, d2))
+	return
+}
+
+// The normal points from A to B
+func collideCapsuleCircle(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	capsule := shapeA.(*Capsule)
+	rA := capsule.Radius
+	rB := shapeB.(*Circle).Radius
+
+	p1 := xfA.MulV(capsule.Center1)
+	q1 := xfA.MulV(capsule.Center2)
+	c := xfB.Position
+
+	// Closest point on the core segment
+	e := q1.Sub(p1)
+	f := Clamp(Dot(c.Sub(p1), e)/Dot(e, e), 0.0, 1.0)
+	q := p1.Add(MulSV(f, e))
+
+	d := c.Sub(q)
+	dist := d.Length()
+	separation := dist - rA - rB
+	if separation > 0.0 {
+		return nil
+	}
+
+	normal := CrossSV(1.0, e)
+	normal = normal.Normalize()
+	if dist > 0.0 {
+		normal = MulSV(1.0/dist, d)
+	}
+
+	pA := q.Add(MulSV(rA, normal))
+	return []*Contact{circleContact(pA, c, rB, normal, separation, FeaturePair{})}
+}
+
+// The normal points from A to B
+//
+// Nearly parallel overlapping capsules get a contact at each end of the
+// overlap, told apart by the end of B they come from. Other poses get a
+// single contact between the closest points of the core segments.
+func collideCapsules(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	capsuleA := shapeA.(*Capsule)
+	capsuleB := shapeB.(*Capsule)
+	rA := capsuleA.Radius
+	rB := capsuleB.Radius
+	radius := rA + rB
+
+	p1 := xfA.MulV(capsuleA.Center1)
+	q1 := xfA.MulV(capsuleA.Center2)
+	p2 := xfB.MulV(capsuleB.Center1)
+	q2 := xfB.MulV(capsuleB.Center2)
+
+	c1, c2, _, _ := segmentDistance(p1, q1, p2, q2)
+	d := c2.Sub(c1)
+	dist := d.Length()
+	if dist > radius {
+		return nil
+	}
+
+	e1 := q1.Sub(p1)
+	length1 := e1.Length()
+	u1 := MulSV(1.0/length1, e1)
+
+	e2 := q2.Sub(p2)
+	u2 := e2.Normalize()
+
+	// Positions of B's ends along A.
+	fp2 := Dot(p2.Sub(p1), u1)
+	fq2 := Dot(q2.Sub(p1), u1)
+	outside := (fp2 <= 0.0 && fq2 <= 0.0) || (fp2 >= length1 && fq2 >= length1)
+
+	const parallelTol = 0.05
+
+	if !outside && math.Abs(CrossVV(u1, u2)) < parallelTol {
+		normal := CrossSV(1.0, u1)
+		mid := MulSV(0.5, p2.Add(q2))
+		if Dot(normal, mid.Sub(p1)) < 0.0 {
+			normal = normal.Negative()
+		}
+
+		// Clip B's core to the extent of A's core.
+		core := [2]Vec2{p2, q2}
+		fs := [2]float64{fp2, fq2}
+		ends := core
+		for i := range ends {
+			j := 1 - i
+			if fs[i] < 0.0 {
+				ends[i] = core[i].Add(MulSV(-fs[i]/(fs[j]-fs[i]), core[j].Sub(core[i])))
+			} else if fs[i] > length1 {
+				ends[i] = core[i].Add(MulSV((fs[i]-length1)/(fs[i]-fs[j]), core[j].Sub(core[i])))
+			}
+		}
+
+		var contacts []*Contact
+		for i, v := range ends {
+			s := Dot(v.Sub(p1), normal)
+			if s > radius {
+				continue
+			}
+
+			var c Contact
+			c.Separation = s - radius
+			c.Normal = normal
+			// midway between the two surfaces
+			c.Position = v.Sub(MulSV(0.5*(s-rA+rB), normal))
+			c.Feature.InEdge2 = EdgeNumbers(i + 1)
+			contacts = append(contacts, &c)
+		}
+		return contacts
+	}
+
+	normal := CrossSV(1.0, u1)
+	if dist > 0.0 {
+		normal = MulSV(1.0/dist, d)
+	}
+
+	var c Contact
+	c.Separation = dist - radius
+	c.Normal = normal
+	c.Position = c1.Add(MulSV(rA+0.5*c.Separation, normal))
+	return []*Contact{&c}
+}
+
+// The normal points from A to B
+func collideBoxCapsule(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	return collideConvex(boxPolygon(shapeA.(*Box)), xfA, capsulePolygon(shapeB.(*Capsule)), xfB)
+}
+
+// The normal points from A to B
+func collidePolygonCapsule(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	return collideConvex(shapeA.(*Polygon), xfA, capsulePolygon(shapeB.(*Capsule)), xfB)
+}
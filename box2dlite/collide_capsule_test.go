@@ -0,0 +1,96 @@
+package box2dlite
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCollideCapsuleCircle(t *testing.T) {
+	capsule := NewCapsule(Vec2{-1.0, 0.0}, Vec2{1.0, 0.0}, 0.5)
+	xfA := NewTransform(Vec2{0.0, 0.0}, 0.0)
+
+	tests := []struct {
+		name       string
+		center     Vec2
+		normal     Vec2
+		separation float64
+	}{
+		{"side", Vec2{0.3, 0.9}, Vec2{0.0, 1.0}, -0.1},
+		{"end", Vec2{1.9, 0.0}, Vec2{1.0, 0.0}, -0.1},
+		{"apart", Vec2{0.3, 1.1}, Vec2{}, 0.0},
+	}
+	for _, tt := range tests {
+		contacts := collideShapes(capsule, xfA, NewCircle(0.5), NewTransform(tt.center, 0.0))
+		if tt.normal == (Vec2{}) {
+			if len(contacts) != 0 {
+				t.Errorf("%s: got %d contacts, want none", tt.name, len(contacts))
+			}
+			continue
+		}
+		if len(contacts) != 1 {
+			t.Errorf("%s: got %d contacts, want 1", tt.name, len(contacts))
+			continue
+		}
+		if c := contacts[0]; !near(c.Normal, tt.normal) || math.Abs(c.Separation-tt.separation) > 1e-9 {
+			t.Errorf("%s: normal %v separation %v, want %v and %v", tt.name, c.Normal, c.Separation, tt.normal, tt.separation)
+		}
+	}
+}
+
+func TestCollideParallelCapsules(t *testing.T) {
+	// Stacked capsules overlapping along x from -0.5 to 1 get a contact at
+	// each end of the overlap.
+	capsule := NewCapsule(Vec2{-1.0, 0.0}, Vec2{1.0, 0.0}, 0.25)
+	xfA := NewTransform(Vec2{0.0, 0.0}, 0.0)
+	xfB := NewTransform(Vec2{0.5, 0.45}, 0.0)
+
+	contacts := collideShapes(capsule, xfA, capsule, xfB)
+	sortContacts(contacts)
+	if len(contacts) != 2 {
+		t.Fatalf("got %d contacts, want 2", len(contacts))
+	}
+	for i, x := range []float64{-0.5, 1.0} {
+		c := contacts[i]
+		if math.Abs(c.Position.X-x) > 1e-9 || !near(c.Normal, Vec2{0.0, 1.0}) || math.Abs(c.Separation+0.05) > 1e-9 {
+			t.Errorf("contact %d at %v normal %v separation %v, want x = %v, (0, 1) and -0.05", i, c.Position, c.Normal, c.Separation, x)
+		}
+	}
+	if contacts[0].Feature.Value() == contacts[1].Feature.Value() {
+		t.Errorf("contacts share feature %v", contacts[0].Feature)
+	}
+}
+
+func TestCollideCrossedCapsules(t *testing.T) {
+	capsule := NewCapsule(Vec2{-1.0, 0.0}, Vec2{1.0, 0.0}, 0.25)
+	xfA := NewTransform(Vec2{0.0, 0.0}, 0.0)
+	// B stands upright on A, 0.05 deep.
+	xfB := NewTransform(Vec2{0.2, 1.45}, math.Pi/2.0)
+
+	contacts := collideShapes(capsule, xfA, capsule, xfB)
+	if len(contacts) != 1 {
+		t.Fatalf("got %d contacts, want 1", len(contacts))
+	}
+	if c := contacts[0]; !near(c.Normal, Vec2{0.0, 1.0}) || math.Abs(c.Separation+0.05) > 1e-9 || math.Abs(c.Position.X-0.2) > 1e-9 {
+		t.Errorf("contact at %v normal %v separation %v, want x = 0.2, (0, 1) and -0.05", c.Position, c.Normal, c.Separation)
+	}
+}
+
+func TestCollideBoxCapsule(t *testing.T) {
+	// A capsule lying on a box gets a contact under each end.
+	box := NewBox(Vec2{4.0, 1.0})
+	capsule := NewCapsule(Vec2{-1.0, 0.0}, Vec2{1.0, 0.0}, 0.25)
+	xfA := NewTransform(Vec2{0.0, 0.0}, 0.0)
+	xfB := NewTransform(Vec2{0.0, 0.7}, 0.0)
+
+	contacts := collideShapes(box, xfA, capsule, xfB)
+	sortContacts(contacts)
+	if len(contacts) != 2 {
+		t.Fatalf("got %d contacts, want 2", len(contacts))
+	}
+	for i, x := range []float64{-1.0, 1.0} {
+		c := contacts[i]
+		if math.Abs(c.Position.X-x) > 1e-9 || !near(c.Normal, Vec2{0.0, 1.0}) || math.Abs(c.Separation+0.05) > 1e-9 {
+			t.Errorf("contact %d at %v normal %v separation %v, want x = %v, (0, 1) and -0.05", i, c.Position, c.Normal, c.Separation, x)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package box2dlite
+
+import (
+	"math"
+)
+
+// circleContact builds the contact between a surface point pA of shape A and
+// a circle of radius rB centered at cB, for the unit normal n from A to B.
+// The contact is placed midway between the two surfaces.
+func circleContact(pA, cB Vec2, rB float64, n Vec2, separation float64, fp FeaturePair) *Contact {
+	pB := cB.Sub(MulSV(rB, n))
+	var c Contact
+	c.Separation = separation
+	c.Normal = n
+	c.Position = MulSV(0.5, pA.Add(pB))
+	c.Feature = fp
+	return &c
+}
+
+// The normal points from A to B
+func collideCircles(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	rA := shapeA.(*Circle).Radius
+	rB := shapeB.(*Circle).Radius
+
+	d := xfB.Position.Sub(xfA.Position)
+	dist := d.Length()
+	separation := dist - rA - rB
+	if separation > 0.0 {
+		return nil
+	}
+
+	normal := Vec2{0.0, 1.0}
+	if dist > 0.0 {
+		normal = MulSV(1.0/dist, d)
+	}
+
+	pA := xfA.Position.Add(MulSV(rA, normal))
+	return []*Contact{circleContact(pA, xfB.Position, rB, normal, separation, FeaturePair{})}
+}
+
+// The normal points from A to B
+//
+// The feature id names the box feature touched by the circle: a face is
+// reported as entering and leaving the same edge, a vertex as entering its
+// first edge and leaving its second, following the box numbering in collide.go.
+func collideBoxCircle(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	box := shapeA.(*Box)
+	h := MulSV(0.5, box.Width)
+	rA := box.Radius
+	r := shapeB.(*Circle).Radius
+
+	// Circle center in the box frame.
+	c := xfA.MulTV(xfB.Position)
+
+	outX := math.Abs(c.X) > h.X
+	outY := math.Abs(c.Y) > h.Y
+
+	var fp FeaturePair
+	var separation float64
+	var n, q Vec2 // local normal and closest point on the box
+
+	switch {
+	case outX || outY:
+		// Center outside the box: the closest point is on the boundary.
+		q = Vec2{Clamp(c.X, -h.X, h.X), Clamp(c.Y, -h.Y, h.Y)}
+		d := c.Sub(q)
+		dist := d.Length()
+		separation = dist - rA - r
+		if separation > 0.0 {
+			return nil
+		}
+		n = MulSV(1.0/dist, d)
+
+		switch {
+		case outX && outY:
+			fp = boxVertexFeature(c)
+		case outX:
+			fp = boxFaceFeature(Vec2{c.X, 0.0})
+		default:
+			fp = boxFaceFeature(Vec2{0.0, c.Y})
+		}
+
+	default:
+		// Center inside the box: push out through the shallowest face.
+		faceX := math.Abs(c.X) - h.X
+		faceY := math.Abs(c.Y) - h.Y
+		if faceX > faceY {
+			n = Vec2{math.Copysign(1.0, c.X), 0.0}
+			q = Vec2{math.Copysign(h.X, c.X), c.Y}
+			separation = faceX - rA - r
+		} else {
+			n = Vec2{0.0, math.Copysign(1.0, c.Y)}
+			q = Vec2{c.X, math.Copysign(h.Y, c.Y)}
+			separation = faceY - rA - r
+		}
+		fp = boxFaceFeature(n)
+	}
+
+	// Move the closest point out onto the rounded surface.
+	q = q.Add(MulSV(rA, n))
+
+	normal := xfA.R.MulV(n)
+	pA := xfA.MulV(q)
+	return []*Contact{circleContact(pA, xfB.Position, r, normal, separation, fp)}
+}
+
+// boxFaceFeature returns the feature of the box face pointed to by the local direction d.
+func boxFaceFeature(d Vec2) FeaturePair {
+	var e EdgeNumbers
+	if math.Abs(d.X) > math.Abs(d.Y) {
+		if d.X > 0.0 {
+			e = EDGE4
+		} else {
+			e = EDGE2
+		}
+	} else {
+		if d.Y > 0.0 {
+			e = EDGE1
+		} else {
+			e = EDGE3
+		}
+	}
+	return FeaturePair{InEdge1: e, OutEdge1: e}
+}
+
+// boxVertexFeature returns the feature of the box vertex in the quadrant of the local point p.
+func boxVertexFeature(p Vec2) FeaturePair {
+	switch {
+	case p.X > 0.0 && p.Y > 0.0:
+		return FeaturePair{InEdge1: EDGE4, OutEdge1: EDGE1}
+	case p.X <= 0.0 && p.Y > 0.0:
+		return FeaturePair{InEdge1: EDGE1, OutEdge1: EDGE2}
+	case p.X <= 0.0:
+		return FeaturePair{InEdge1: EDGE2, OutEdge1: EDGE3}
+	default:
+		return FeaturePair{InEdge1: EDGE3, OutEdge1: EDGE4}
+	}
+}
@@ -0,0 +1,356 @@
+package box2dlite
+
+import (
+	"math"
+)
+
+// Edges are collided in the edge's frame. One-sided edges use their ghost
+// vertices to reject or snap normals that point into a neighboring segment,
+// so shapes sliding over a smooth chain never hit the internal corners.
+// See https://box2d.org/posts/2020/06/ghost-collisions/
+
+// edgeAxis is a candidate separating axis, either a side of the edge or a
+// face of the polygon.
+type edgeAxis struct {
+	polygon    bool
+	index      int
+	separation float64
+	normal     Vec2
+}
+
+// computeEdgeSeparation finds the edge side the polygon is least deep behind.
+func computeEdgeSeparation(poly *Polygon, v1 Vec2, normal1 Vec2) edgeAxis {
+	axis := edgeAxis{separation: -math.MaxFloat64}
+
+	axes := [2]Vec2{normal1, normal1.Negative()}
+	for j, n := range axes {
+		s := math.MaxFloat64
+		for _, v := range poly.Vertices {
+			s = math.Min(s, Dot(n, v.Sub(v1)))
+		}
+
+		if s > axis.separation {
+			axis.index = j
+			axis.separation = s
+			axis.normal = n
+		}
+	}
+
+	return axis
+}
+
+// computePolygonSeparation finds the polygon face that separates the edge the most.
+func computePolygonSeparation(poly *Polygon, v1, v2 Vec2) edgeAxis {
+	axis := edgeAxis{polygon: true, separation: -math.MaxFloat64}
+
+	for i, pn := range poly.Normals {
+		n := pn.Negative()
+		s := math.Min(Dot(n, poly.Vertices[i].Sub(v1)), Dot(n, poly.Vertices[i].Sub(v2)))
+
+		if s > axis.separation {
+			axis.index = i
+			axis.separation = s
+			axis.normal = n
+		}
+	}
+
+	return axis
+}
+
+// collideEdgeConvex collides an edge with a convex polygon inflated by its radius.
+//
+// The normal points from A to B
+func collideEdgeConvex(edge *Edge, xfA Transform, poly *Polygon, xfB Transform) []*Contact {
+	radiusB := poly.Radius
+
+	// Work in the edge's frame.
+	xf := xfA.MulT(xfB)
+
+	n := len(poly.Vertices)
+	polyB := &Polygon{
+		Vertices: make([]Vec2, n),
+		Normals:  make([]Vec2, n),
+	}
+	var centroid Vec2
+	for i := range poly.Vertices {
+		polyB.Vertices[i] = xf.MulV(poly.Vertices[i])
+		polyB.Normals[i] = xf.R.MulV(poly.Normals[i])
+		centroid = centroid.Add(polyB.Vertices[i])
+	}
+	centroid = MulSV(1.0/float64(n), centroid)
+
+	v1 := edge.Vertex1
+	v2 := edge.Vertex2
+	edge1 := v2.Sub(v1)
+	edge1 = edge1.Normalize()
+
+	// Normal points to the right for a CCW winding
+	normal1 := CrossVS(edge1, 1.0)
+	offset1 := Dot(normal1, centroid.Sub(v1))
+
+	if edge.OneSided && offset1 < 0.0 {
+		return nil
+	}
+
+	edgeAxis := computeEdgeSeparation(polyB, v1, normal1)
+	if edgeAxis.separation > radiusB {
+		return nil
+	}
+
+	polygonAxis := computePolygonSeparation(polyB, v1, v2)
+	if polygonAxis.separation > radiusB {
+		return nil
+	}
+
+	// Use hysteresis for jitter reduction.
+	relativeTol := 0.98
+	absoluteTol := 0.001
+
+	primaryAxis := edgeAxis
+	if polygonAxis.separation-radiusB > relativeTol*(edgeAxis.separation-radiusB)+absoluteTol {
+		primaryAxis = polygonAxis
+	}
+
+	if edge.OneSided {
+		// Check the normal against the Gauss map of the joints. Normals
+		// pointing into a convex neighbor belong to that neighbor and are
+		// skipped, normals at a concave joint snap to the edge normal.
+		edge0 := v1.Sub(edge.Vertex0)
+		edge0 = edge0.Normalize()
+		normal0 := CrossVS(edge0, 1.0)
+		convex1 := CrossVV(edge0, edge1) >= 0.0
+
+		edge2 := edge.Vertex3.Sub(v2)
+		edge2 = edge2.Normalize()
+		normal2 := CrossVS(edge2, 1.0)
+		convex2 := CrossVV(edge1, edge2) >= 0.0
+
+		sinTol := 0.1
+		side1 := Dot(primaryAxis.normal, edge1) <= 0.0
+
+		if side1 {
+			if convex1 {
+				if CrossVV(primaryAxis.normal, normal0) > sinTol {
+					return nil
+				}
+			} else {
+				primaryAxis = edgeAxis
+			}
+		} else {
+			if convex2 {
+				if CrossVV(normal2, primaryAxis.normal) > sinTol {
+					return nil
+				}
+			} else {
+				primaryAxis = edgeAxis
+			}
+		}
+	}
+
+	var incidentEdge [2]ClipVertex
+	var refV1, refV2, refNormal, sideNormal Vec2
+	var negEdge, posEdge EdgeNumbers
+	var refRadius float64
+	flip := false
+
+	if !primaryAxis.polygon {
+		// The edge is the reference face.
+		refV1, refV2 = v1, v2
+		refNormal = primaryAxis.normal
+		sideNormal = edge1
+		negEdge = edge.edgeNumber(-1)
+		posEdge = edge.edgeNumber(1)
+
+		// Search for the polygon face most anti-parallel to the edge normal.
+		computeIncidentPolygonEdge(incidentEdge[:], polyB, NewTransform(Vec2{0.0, 0.0}, 0.0), refNormal)
+	} else {
+		// A polygon face is the reference face.
+		i1 := primaryAxis.index
+		i2 := (i1 + 1) % n
+		refV1, refV2 = polyB.Vertices[i1], polyB.Vertices[i2]
+		refNormal = polyB.Normals[i1]
+		sideNormal = refV2.Sub(refV1)
+		sideNormal = sideNormal.Normalize()
+		negEdge = poly.edgeNumber(i1 - 1)
+		posEdge = poly.edgeNumber(i1 + 1)
+		refRadius = radiusB
+		flip = true
+
+		incidentEdge[0].v = v2
+		incidentEdge[0].fp.InEdge2, incidentEdge[0].fp.OutEdge2 = edge.vertexFeature(1)
+		incidentEdge[1].v = v1
+		incidentEdge[1].fp.InEdge2, incidentEdge[1].fp.OutEdge2 = edge.vertexFeature(0)
+	}
+
+	var (
+		clipPoints1 [2]ClipVertex
+		clipPoints2 [2]ClipVertex
+	)
+
+	np := ClipSegmentToLine(clipPoints1[:], incidentEdge[:], sideNormal.Negative(), -Dot(sideNormal, refV1), negEdge)
+
+	if np < 2 {
+		return nil
+	}
+
+	np = ClipSegmentToLine(clipPoints2[:], clipPoints1[:], sideNormal, Dot(sideNormal, refV2), posEdge)
+
+	if np < 2 {
+		return nil
+	}
+
+	normal := xfA.R.MulV(primaryAxis.normal)
+
+	var contacts []*Contact
+	for i := 0; i < 2; i++ {
+		separation := Dot(refNormal, clipPoints2[i].v.Sub(refV1))
+
+		if separation <= radiusB {
+			var c Contact
+			c.Separation = separation - radiusB
+			c.Normal = normal
+			// slide contact point onto reference face
+			c.Position = xfA.MulV(clipPoints2[i].v.Sub(MulSV(separation-refRadius, refNormal)))
+			c.Feature = clipPoints2[i].fp
+			if flip {
+				c.Feature.Flip()
+			}
+			contacts = append(contacts, &c)
+		}
+	}
+
+	return contacts
+}
+
+// The normal points from A to B
+func collideEdgeBox(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	return collideEdgeConvex(shapeA.(*Edge), xfA, boxPolygon(shapeB.(*Box)), xfB)
+}
+
+// The normal points from A to B
+func collideEdgePolygon(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	return collideEdgeConvex(shapeA.(*Edge), xfA, shapeB.(*Polygon), xfB)
+}
+
+// The normal points from A to B
+func collideEdgeCapsule(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	return collideEdgeConvex(shapeA.(*Edge), xfA, capsulePolygon(shapeB.(*Capsule)), xfB)
+}
+
+// The normal points from A to B
+func collideEdgeCircle(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	edge := shapeA.(*Edge)
+	r := shapeB.(*Circle).Radius
+
+	// Circle center in the edge's frame.
+	Q := xfA.MulTV(xfB.Position)
+
+	A := edge.Vertex1
+	B := edge.Vertex2
+	e := B.Sub(A)
+
+	// Normal points to the right for a CCW winding
+	n := CrossVS(e, 1.0)
+	offset := Dot(n, Q.Sub(A))
+
+	if edge.OneSided && offset < 0.0 {
+		return nil
+	}
+
+	// Barycentric coordinates
+	u := Dot(e, B.Sub(Q))
+	v := Dot(e, Q.Sub(A))
+
+	var P Vec2
+	var fp FeaturePair
+
+	switch {
+	case v <= 0.0:
+		// Region A
+		P = A
+		// Leave the circle to the previous segment if it lies in its face region.
+		if edge.OneSided {
+			e0 := A.Sub(edge.Vertex0)
+			if Dot(e0, A.Sub(Q)) > 0.0 {
+				return nil
+			}
+		}
+		fp.InEdge1, fp.OutEdge1 = edge.vertexFeature(0)
+
+	case u <= 0.0:
+		// Region B
+		P = B
+		// Leave the circle to the next segment if it lies in its face region.
+		if edge.OneSided {
+			e2 := edge.Vertex3.Sub(B)
+			if Dot(e2, Q.Sub(B)) > 0.0 {
+				return nil
+			}
+		}
+		fp.InEdge1, fp.OutEdge1 = edge.vertexFeature(1)
+
+	default:
+		// Region AB
+		P = MulSV(u, A)
+		P = P.Add(MulSV(v, B))
+		P = MulSV(1.0/Dot(e, e), P)
+		fp.InEdge1 = edge.edgeNumber(0)
+		fp.OutEdge1 = edge.edgeNumber(0)
+	}
+
+	d := Q.Sub(P)
+	dist := d.Length()
+	if dist > r {
+		return nil
+	}
+
+	normal := n.Normalize()
+	if offset < 0.0 {
+		normal = normal.Negative()
+	}
+	if dist > 0.0 {
+		normal = MulSV(1.0/dist, d)
+	}
+
+	return []*Contact{circleContact(xfA.MulV(P), xfB.Position, r, xfA.R.MulV(normal), dist-r, fp)}
+}
+
+// collideChain collides each segment of a chain near shape B.
+//
+// The normal points from A to B
+func collideChain(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	chain := shapeA.(*Chain)
+	aabb := shapeB.ComputeAABB(xfB)
+
+	var contacts []*Contact
+	for i := 0; i < chain.ChildCount(); i++ {
+		edge := chain.ChildEdge(i)
+		if !aabb.Overlaps(edge.ComputeAABB(xfA)) {
+			continue
+		}
+		contacts = append(contacts, collideShapes(edge, xfA, shapeB, xfB)...)
+	}
+	return contacts
+}
+
+// collideHeightfield collides the cells of a heightfield under shape B.
+//
+// The normal points from A to B
+func collideHeightfield(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	field := shapeA.(*Heightfield)
+
+	// Bounds of B in the heightfield's frame.
+	aabb := shapeB.ComputeAABB(xfA.MulT(xfB))
+	i1, i2 := field.cellRange(aabb.Min.X, aabb.Max.X)
+
+	var contacts []*Contact
+	for i := i1; i <= i2; i++ {
+		// Skip cells entirely above or below B.
+		h1, h2 := field.Heights[i], field.Heights[i+1]
+		if math.Max(h1, h2) < aabb.Min.Y || math.Min(h1, h2) > aabb.Max.Y {
+			continue
+		}
+		contacts = append(contacts, collideShapes(field.ChildEdge(i), xfA, shapeB, xfB)...)
+	}
+	return contacts
+}
@@ -0,0 +1,80 @@
+package box2dlite
+
+import (
+	"math"
+	"testing"
+)
+
+// Ground facing up, made of segments listed from right to left
+var flatGround = []Vec2{{4.0, 0.0}, {0.0, 0.0}, {-4.0, 0.0}}
+
+// cornerBox is a box 0.05 deep in the ground and 0.03 past the joint at x = 0.
+func cornerBox() (*Box, Transform) {
+	return NewBox(Vec2{1.0, 1.0}), NewTransform(Vec2{0.47, 0.45}, 0.0)
+}
+
+func TestCollideEdgeGhostVertices(t *testing.T) {
+	box, xfB := cornerBox()
+	xfA := NewTransform(Vec2{0.0, 0.0}, 0.0)
+
+	// Alone, the end of the left segment catches the box's side.
+	edge := NewEdge(flatGround[1], flatGround[2])
+	contacts := collideShapes(edge, xfA, box, xfB)
+	if len(contacts) == 0 || math.Abs(contacts[0].Normal.Y) > 1e-9 {
+		t.Fatalf("two-sided edge: got %d contacts, want one pushing the box sideways", len(contacts))
+	}
+
+	// With the ghost vertex of the right segment, the left one never pushes
+	// the box sideways; the right segment holds it up.
+	edge = NewOneSidedEdge(flatGround[0], flatGround[1], flatGround[2], Vec2{-8.0, 0.0})
+	contacts = collideShapes(edge, xfA, box, xfB)
+	for i, c := range contacts {
+		if !near(c.Normal, Vec2{0.0, 1.0}) {
+			t.Errorf("one-sided edge: contact %d normal %v, want (0, 1)", i, c.Normal)
+		}
+	}
+}
+
+func TestCollideChainSmoothJoint(t *testing.T) {
+	box, xfB := cornerBox()
+	xfA := NewTransform(Vec2{0.0, 0.0}, 0.0)
+
+	contacts := collideShapes(NewChain(flatGround), xfA, box, xfB)
+	if len(contacts) != 2 {
+		t.Fatalf("got %d contacts, want 2", len(contacts))
+	}
+	for i, c := range contacts {
+		if !near(c.Normal, Vec2{0.0, 1.0}) || math.Abs(c.Separation+0.05) > 1e-9 {
+			t.Errorf("contact %d normal %v separation %v, want (0, 1) and -0.05", i, c.Normal, c.Separation)
+		}
+	}
+	if contacts[0].Feature.Value() == contacts[1].Feature.Value() {
+		t.Errorf("contacts share feature %v", contacts[0].Feature)
+	}
+}
+
+func TestCollideOneSidedEdgeFromBehind(t *testing.T) {
+	edge := NewOneSidedEdge(Vec2{8.0, 0.0}, flatGround[0], flatGround[1], flatGround[2])
+	xfA := NewTransform(Vec2{0.0, 0.0}, 0.0)
+
+	// Just below the edge, the box passes through.
+	box := NewBox(Vec2{1.0, 1.0})
+	xfB := NewTransform(Vec2{2.0, -0.45}, 0.0)
+	if contacts := collideShapes(edge, xfA, box, xfB); len(contacts) != 0 {
+		t.Errorf("box behind: got %d contacts, want none", len(contacts))
+	}
+
+	circle := NewCircle(0.5)
+	if contacts := collideShapes(edge, xfA, circle, xfB); len(contacts) != 0 {
+		t.Errorf("circle behind: got %d contacts, want none", len(contacts))
+	}
+
+	// Just above, they are pushed up.
+	xfB = NewTransform(Vec2{2.0, 0.45}, 0.0)
+	for _, s := range []Shape{box, circle} {
+		contacts := collideShapes(edge, xfA, s, xfB)
+		if len(contacts) == 0 || !near(contacts[0].Normal, Vec2{0.0, 1.0}) {
+			t.Errorf("%T in front: got %d contacts, want them pushed up", s, len(contacts))
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package box2dlite
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCollideHeightfieldFlat(t *testing.T) {
+	field := NewHeightfield([]float64{0.0, 0.0, 0.0, 0.0, 0.0}, 1.0)
+	xfA := NewTransform(Vec2{0.0, 0.0}, 0.0)
+
+	// A box inside a cell rests on it.
+	box := NewBox(Vec2{0.6, 0.6})
+	xfB := NewTransform(Vec2{2.5, 0.25}, 0.0)
+	contacts := collideShapes(field, xfA, box, xfB)
+	if len(contacts) != 2 {
+		t.Fatalf("got %d contacts, want 2", len(contacts))
+	}
+	for i, c := range contacts {
+		if !near(c.Normal, Vec2{0.0, 1.0}) || math.Abs(c.Separation+0.05) > 1e-9 {
+			t.Errorf("contact %d normal %v separation %v, want (0, 1) and -0.05", i, c.Normal, c.Separation)
+		}
+	}
+	if contacts[0].Feature.Value() == contacts[1].Feature.Value() {
+		t.Errorf("contacts share feature %v", contacts[0].Feature)
+	}
+
+	// A box across the joint of two cells is pushed up by both, without
+	// catching on the joint.
+	xfB = NewTransform(Vec2{2.0, 0.25}, 0.0)
+	contacts = collideShapes(field, xfA, box, xfB)
+	if len(contacts) == 0 {
+		t.Fatal("across the joint: no contacts")
+	}
+	for i, c := range contacts {
+		if !near(c.Normal, Vec2{0.0, 1.0}) {
+			t.Errorf("across the joint: contact %d normal %v, want (0, 1)", i, c.Normal)
+		}
+	}
+
+	// Past the ends of the field there is nothing to hit.
+	xfB = NewTransform(Vec2{-1.0, 0.25}, 0.0)
+	if contacts := collideShapes(field, xfA, box, xfB); len(contacts) != 0 {
+		t.Errorf("past the end: got %d contacts, want none", len(contacts))
+	}
+}
+
+func TestCollideHeightfieldValley(t *testing.T) {
+	// A circle resting in a V touches both slopes.
+	field := NewHeightfield([]float64{1.0, 0.0, 1.0}, 1.0)
+	xfA := NewTransform(Vec2{0.0, 0.0}, 0.0)
+
+	r := 0.5
+	y := r*math.Sqrt2 - 0.01
+	contacts := collideShapes(field, xfA, NewCircle(r), NewTransform(Vec2{1.0, y}, 0.0))
+	if len(contacts) != 2 {
+		t.Fatalf("got %d contacts, want 2", len(contacts))
+	}
+	sortContacts(contacts)
+
+	s := math.Sqrt2 / 2.0
+	for i, n := range []Vec2{{s, s}, {-s, s}} {
+		if c := contacts[i]; !near(c.Normal, n) || c.Separation >= 0.0 {
+			t.Errorf("contact %d normal %v separation %v, want %v and overlapping", i, c.Normal, c.Separation, n)
+		}
+	}
+}
+
+func TestCollideHeightfieldFromBelow(t *testing.T) {
+	field := NewHeightfield([]float64{0.0, 0.0, 0.0}, 1.0)
+	xfA := NewTransform(Vec2{0.0, 0.0}, 0.0)
+
+	xfB := NewTransform(Vec2{1.0, -0.45}, 0.0)
+	if contacts := collideShapes(field, xfA, NewCircle(0.5), xfB); len(contacts) != 0 {
+		t.Errorf("got %d contacts from below, want none", len(contacts))
+	}
+}
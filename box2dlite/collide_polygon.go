@@ -0,0 +1,272 @@
+package box2dlite
+
+import (
+	"math"
+)
+
+// Polygons are collided with the same SAT and clipping scheme as boxes in
+// collide.go, generalized from 4 to N edges. Features follow the box
+// convention: a vertex enters through the edge ending at it and leaves
+// through the edge starting at it, and clipped points take the number of the
+// neighboring edge of the reference face that clipped them.
+
+// findMaxSeparation returns the edge of polyA whose plane separates polyB the most.
+func findMaxSeparation(polyA *Polygon, xfA Transform, polyB *Polygon, xfB Transform) (int, float64) {
+	bestEdge := 0
+	maxSeparation := -math.MaxFloat64
+
+	for i, n := range polyA.Normals {
+		// Edge plane of A in B's frame.
+		normal := xfB.R.MulTV(xfA.R.MulV(n))
+		v := xfB.MulTV(xfA.MulV(polyA.Vertices[i]))
+
+		separation := math.MaxFloat64
+		for _, vB := range polyB.Vertices {
+			if s := Dot(normal, vB.Sub(v)); s < separation {
+				separation = s
+			}
+		}
+
+		if separation > maxSeparation {
+			maxSeparation = separation
+			bestEdge = i
+		}
+	}
+
+	return bestEdge, maxSeparation
+}
+
+// computeIncidentPolygonEdge finds the edge of poly most anti-parallel to the
+// world normal of the reference face and returns its index.
+func computeIncidentPolygonEdge(c []ClipVertex, poly *Polygon, xf Transform, normal Vec2) int {
+	n := xf.R.MulTV(normal)
+
+	index := 0
+	minDot := math.MaxFloat64
+	for i, ni := range poly.Normals {
+		if dot := Dot(n, ni); dot < minDot {
+			minDot = dot
+			index = i
+		}
+	}
+
+	i1 := index
+	i2 := (index + 1) % len(poly.Vertices)
+
+	c[0].v = xf.MulV(poly.Vertices[i1])
+	c[0].fp.InEdge2 = poly.edgeNumber(i1 - 1)
+	c[0].fp.OutEdge2 = poly.edgeNumber(i1)
+
+	c[1].v = xf.MulV(poly.Vertices[i2])
+	c[1].fp.InEdge2 = poly.edgeNumber(i1)
+	c[1].fp.OutEdge2 = poly.edgeNumber(i2)
+
+	return index
+}
+
+// vertexFeature returns the feature of vertex i of poly.
+func (s *Polygon) vertexFeature(i int) (EdgeNumbers, EdgeNumbers) {
+	return s.edgeNumber(i - 1), s.edgeNumber(i)
+}
+
+// The normal points from A to B
+func collidePolygons(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	return collideConvex(shapeA.(*Polygon), xfA, shapeB.(*Polygon), xfB)
+}
+
+// The normal points from A to B
+func collideBoxPolygon(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	return collideConvex(boxPolygon(shapeA.(*Box)), xfA, shapeB.(*Polygon), xfB)
+}
+
+// collideConvex collides two convex polygons inflated by their radii.
+//
+// The normal points from A to B
+func collideConvex(polyA *Polygon, xfA Transform, polyB *Polygon, xfB Transform) []*Contact {
+	radiusA := polyA.Radius
+	radiusB := polyB.Radius
+	radius := radiusA + radiusB
+
+	edgeA, separationA := findMaxSeparation(polyA, xfA, polyB, xfB)
+	if separationA > radius {
+		return nil
+	}
+
+	edgeB, separationB := findMaxSeparation(polyB, xfB, polyA, xfA)
+	if separationB > radius {
+		return nil
+	}
+
+	// Prefer A as the reference polygon, like the box code does.
+	relativeTol := 0.98
+	absoluteTol := 0.001
+
+	poly1, xf1, radius1, edge1 := polyA, xfA, radiusA, edgeA
+	poly2, xf2 := polyB, xfB
+	coreSeparation := separationA
+	flip := false
+	if separationB > relativeTol*separationA+absoluteTol {
+		poly1, xf1, radius1, edge1 = polyB, xfB, radiusB, edgeB
+		poly2, xf2 = polyA, xfA
+		coreSeparation = separationB
+		flip = true
+	}
+
+	// Reference face
+	n := len(poly1.Vertices)
+	v1 := xf1.MulV(poly1.Vertices[edge1])
+	v2 := xf1.MulV(poly1.Vertices[(edge1+1)%n])
+
+	frontNormal := xf1.R.MulV(poly1.Normals[edge1])
+	front := Dot(frontNormal, v1)
+
+	var incidentEdge [2]ClipVertex
+	edge2 := computeIncidentPolygonEdge(incidentEdge[:], poly2, xf2, frontNormal)
+
+	var contacts []*Contact
+
+	// When the cores are apart, only the rounding can touch. If the closest
+	// features are two vertices, the normal runs between them instead of
+	// along the reference face.
+	if coreSeparation > 0.0005 {
+		_, _, f1, f2 := segmentDistance(v1, v2, incidentEdge[0].v, incidentEdge[1].v)
+		if (f1 == 0.0 || f1 == 1.0) && (f2 == 0.0 || f2 == 1.0) {
+			i1 := edge1 + int(f1)
+			i2 := edge2 + int(f2)
+			p1 := xf1.MulV(poly1.Vertices[i1%n])
+			p2 := incidentEdge[int(f2)].v
+
+			d := p2.Sub(p1)
+			dist := d.Length()
+			if dist > radius {
+				return nil
+			}
+
+			var c Contact
+			c.Separation = dist - radius
+			c.Normal = MulSV(1.0/dist, d)
+			c.Position = p1.Add(MulSV(radius1, c.Normal))
+			c.Feature.InEdge1, c.Feature.OutEdge1 = poly1.vertexFeature(i1)
+			c.Feature.InEdge2, c.Feature.OutEdge2 = poly2.vertexFeature(i2)
+			if flip {
+				c.Normal = c.Normal.Negative()
+				c.Feature.Flip()
+			}
+			return append(contacts, &c)
+		}
+	}
+
+	sideNormal := v2.Sub(v1)
+	sideNormal = sideNormal.Normalize()
+	negSide := -Dot(sideNormal, v1)
+	posSide := Dot(sideNormal, v2)
+	negEdge := poly1.edgeNumber(edge1 - 1)
+	posEdge := poly1.edgeNumber(edge1 + 1)
+
+	var (
+		clipPoints1 [2]ClipVertex
+		clipPoints2 [2]ClipVertex
+	)
+
+	// Clip to the side behind the first reference vertex
+	np := ClipSegmentToLine(clipPoints1[:], incidentEdge[:], sideNormal.Negative(), negSide, negEdge)
+
+	if np < 2 {
+		return nil
+	}
+
+	// Clip to the side beyond the second reference vertex
+	np = ClipSegmentToLine(clipPoints2[:], clipPoints1[:], sideNormal, posSide, posEdge)
+
+	if np < 2 {
+		return nil
+	}
+
+	normal := frontNormal
+	if flip {
+		normal = normal.Negative()
+	}
+
+	for i := 0; i < 2; i++ {
+		var separation float64 = Dot(frontNormal, clipPoints2[i].v) - front
+
+		if separation <= radius {
+			var c Contact
+			c.Separation = separation - radius
+			c.Normal = normal
+			// slide contact point onto reference face (easy to cull)
+			c.Position = clipPoints2[i].v.Sub(MulSV(separation-radius1, frontNormal))
+			c.Feature = clipPoints2[i].fp
+			if flip {
+				c.Feature.Flip()
+			}
+			contacts = append(contacts, &c)
+		}
+	}
+
+	return contacts
+}
+
+// The normal points from A to B
+func collidePolygonCircle(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+	poly := shapeA.(*Polygon)
+	rA := poly.Radius
+	r := shapeB.(*Circle).Radius
+
+	// Circle center in the polygon frame.
+	c := xfA.MulTV(xfB.Position)
+
+	// Find the face of minimum penetration.
+	n := len(poly.Vertices)
+	face := 0
+	separation := -math.MaxFloat64
+	for i := 0; i < n; i++ {
+		s := Dot(poly.Normals[i], c.Sub(poly.Vertices[i]))
+		if s > rA+r {
+			return nil
+		}
+		if s > separation {
+			separation = s
+			face = i
+		}
+	}
+
+	v1 := poly.Vertices[face]
+	v2 := poly.Vertices[(face+1)%n]
+
+	fp := FeaturePair{InEdge1: poly.edgeNumber(face), OutEdge1: poly.edgeNumber(face)}
+	normal := poly.Normals[face]
+	q := c.Sub(MulSV(separation, normal))
+
+	// Outside the polygon, the closest feature may be a vertex of the face.
+	if separation > 0.0 {
+		u1 := Dot(c.Sub(v1), v2.Sub(v1))
+		u2 := Dot(c.Sub(v2), v1.Sub(v2))
+
+		vertex := -1
+		if u1 <= 0.0 {
+			vertex = face
+			q = v1
+		} else if u2 <= 0.0 {
+			vertex = face + 1
+			q = v2
+		}
+
+		if vertex >= 0 {
+			d := c.Sub(q)
+			dist := d.Length()
+			if dist > rA+r {
+				return nil
+			}
+			normal = MulSV(1.0/dist, d)
+			separation = dist
+			fp = FeaturePair{InEdge1: poly.edgeNumber(vertex - 1), OutEdge1: poly.edgeNumber(vertex)}
+		}
+	}
+
+	separation -= rA + r
+
+	// Move the closest point out onto the rounded surface.
+	q = q.Add(MulSV(rA, normal))
+	return []*Contact{circleContact(xfA.MulV(q), xfB.Position, r, xfA.R.MulV(normal), separation, fp)}
+}
@@ -0,0 +1,130 @@
+package box2dlite
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+// square returns a counter-clockwise square polygon of side w.
+func square(w float64) *Polygon {
+	h := 0.5 * w
+	return NewPolygon([]Vec2{{h, h}, {-h, h}, {-h, -h}, {h, -h}})
+}
+
+// sortContacts orders contacts by x, then y.
+func sortContacts(contacts []*Contact) {
+	sort.Slice(contacts, func(i, j int) bool {
+		pi, pj := contacts[i].Position, contacts[j].Position
+		if pi.X != pj.X {
+			return pi.X < pj.X
+		}
+		return pi.Y < pj.Y
+	})
+}
+
+func near(a, b Vec2) bool {
+	d := a.Sub(b)
+	return d.Length() < 1e-9
+}
+
+func TestCollidePolygonsClipsToReferenceFace(t *testing.T) {
+	// A wide plank resting 0.1 deep on a narrow block: the plank's bottom
+	// edge is clipped to the sides of the block's top face.
+	block := square(1.0)
+	plank := NewPolygon([]Vec2{{1.5, 0.25}, {-1.5, 0.25}, {-1.5, -0.25}, {1.5, -0.25}})
+
+	xfA := NewTransform(Vec2{0.0, 0.0}, 0.0)
+	xfB := NewTransform(Vec2{0.0, 0.65}, 0.0)
+	contacts := collideShapes(block, xfA, plank, xfB)
+	sortContacts(contacts)
+
+	if len(contacts) != 2 {
+		t.Fatalf("got %d contacts, want 2", len(contacts))
+	}
+	for i, want := range []Vec2{{-0.5, 0.5}, {0.5, 0.5}} {
+		c := contacts[i]
+		if !near(c.Position, want) {
+			t.Errorf("contact %d at %v, want %v", i, c.Position, want)
+		}
+		if !near(c.Normal, Vec2{0.0, 1.0}) {
+			t.Errorf("contact %d normal %v, want it from the block to the plank", i, c.Normal)
+		}
+		if math.Abs(c.Separation+0.1) > 1e-9 {
+			t.Errorf("contact %d separation %v, want -0.1", i, c.Separation)
+		}
+	}
+	if contacts[0].Feature.Value() == contacts[1].Feature.Value() {
+		t.Errorf("contacts share feature %v", contacts[0].Feature)
+	}
+}
+
+func TestCollidePolygonsVertexOnFace(t *testing.T) {
+	// A triangle standing on its tip, 0.05 into a box: the other clip point
+	// is above the face, so only the tip touches.
+	triangle := NewPolygon([]Vec2{{0.0, -0.5}, {0.5, 0.5}, {-0.5, 0.5}})
+	xfA := NewTransform(Vec2{0.0, 0.0}, 0.0)
+	xfB := NewTransform(Vec2{0.0, 0.95}, 0.0)
+
+	contacts := collideShapes(square(1.0), xfA, triangle, xfB)
+	if len(contacts) != 1 {
+		t.Fatalf("got %d contacts, want 1", len(contacts))
+	}
+	if c := contacts[0]; !near(c.Position, Vec2{0.0, 0.5}) || math.Abs(c.Separation+0.05) > 1e-9 {
+		t.Errorf("contact at %v with separation %v, want the tip at (0, 0.5), 0.05 deep", c.Position, c.Separation)
+	}
+}
+
+func TestCollidePolygonsSeparated(t *testing.T) {
+	// The axes of both polygons overlap, but a diagonal of the triangle
+	// separates them.
+	triangle := NewPolygon([]Vec2{{0.5, -0.5}, {0.5, 0.5}, {-0.5, -0.5}})
+	xfA := NewTransform(Vec2{0.0, 0.0}, 0.0)
+	xfB := NewTransform(Vec2{-0.9, 0.9}, 0.0)
+
+	if contacts := collideShapes(triangle, xfA, square(1.0), xfB); contacts != nil {
+		t.Errorf("got %d contacts for separated polygons", len(contacts))
+	}
+}
+
+func TestCollidePolygonMatchesBox(t *testing.T) {
+	// A polygon with a box's corners gets the box's contacts and features.
+	box := NewBox(Vec2{1.0, 1.0})
+	poly := square(1.0)
+	xfA := NewTransform(Vec2{0.0, 0.0}, 0.0)
+	xfB := NewTransform(Vec2{0.3, 0.9}, 0.2)
+
+	want := collideShapes(box, xfA, box, xfB)
+	got := collideShapes(poly, xfA, poly, xfB)
+	if len(got) != len(want) || len(want) == 0 {
+		t.Fatalf("got %d contacts, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if !near(got[i].Position, want[i].Position) || got[i].Feature != want[i].Feature {
+			t.Errorf("contact %d: got %v %v, want %v %v", i, got[i].Position, got[i].Feature, want[i].Position, want[i].Feature)
+		}
+	}
+}
+
+func TestCollidePolygonCircle(t *testing.T) {
+	xfA := NewTransform(Vec2{0.0, 0.0}, 0.0)
+
+	// On a face
+	contacts := collideShapes(square(1.0), xfA, NewCircle(0.5), NewTransform(Vec2{0.2, 0.9}, 0.0))
+	if len(contacts) != 1 {
+		t.Fatalf("face: got %d contacts, want 1", len(contacts))
+	}
+	if c := contacts[0]; !near(c.Normal, Vec2{0.0, 1.0}) || math.Abs(c.Separation+0.1) > 1e-9 {
+		t.Errorf("face: normal %v separation %v, want (0, 1) and -0.1", c.Normal, c.Separation)
+	}
+
+	// Off a corner
+	d := 0.4 / math.Sqrt2
+	contacts = collideShapes(square(1.0), xfA, NewCircle(0.5), NewTransform(Vec2{0.5 + d, 0.5 + d}, 0.0))
+	if len(contacts) != 1 {
+		t.Fatalf("corner: got %d contacts, want 1", len(contacts))
+	}
+	if c := contacts[0]; !near(c.Normal, Vec2{math.Sqrt2 / 2.0, math.Sqrt2 / 2.0}) || math.Abs(c.Separation+0.1) > 1e-9 {
+		t.Errorf("corner: normal %v separation %v, want the diagonal and -0.1", c.Normal, c.Separation)
+	}
+}
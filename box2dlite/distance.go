@@ -0,0 +1,447 @@
+package box2dlite
+
+import (
+	"math"
+)
+
+// Distances are found with GJK, which only needs the support points of the
+// two shapes. Built-in shapes are reduced to a polygon core and a radius,
+// so GJK sees a polytope and the radius is taken off at the end; other
+// shapes are used through Shape.Support as they are.
+// See https://box2d.org/files/ErinCatto_GJK_GDC2010.pdf
+
+// DistanceOutput holds the closest points of two shapes and the distance
+// between them. The points coincide when the shapes overlap.
+type DistanceOutput struct {
+	PointA, PointB Vec2
+	Distance       float64
+
+	// GJK iterations used
+	Iterations int
+}
+
+const (
+	gjkMaxIterations = 20
+	gjkEpsilon       = 1.0e-9
+)
+
+// distanceProxy is a convex core seen through its support function,
+// rounded by radius.
+type distanceProxy struct {
+	support func(d Vec2) Vec2
+	radius  float64
+}
+
+func makeDistanceProxy(s Shape) distanceProxy {
+	switch s := s.(type) {
+	case *Box:
+		return polygonProxy(boxPolygon(s))
+	case *Polygon:
+		return polygonProxy(s)
+	case *Capsule:
+		return polygonProxy(capsulePolygon(s))
+	case *Edge:
+		return polygonProxy(&Polygon{Vertices: []Vec2{s.Vertex1, s.Vertex2}})
+	case *Circle:
+		return distanceProxy{
+			support: func(d Vec2) Vec2 { return Vec2{0.0, 0.0} },
+			radius:  s.Radius,
+		}
+	}
+	return distanceProxy{support: s.Support}
+}
+
+func polygonProxy(poly *Polygon) distanceProxy {
+	return distanceProxy{
+		support: func(d Vec2) Vec2 {
+			best := poly.Vertices[0]
+			for _, v := range poly.Vertices[1:] {
+				if Dot(v, d) > Dot(best, d) {
+					best = v
+				}
+			}
+			return best
+		},
+		radius: poly.Radius,
+	}
+}
+
+// simplexVertex is a point of the Minkowski difference B - A.
+type simplexVertex struct {
+	wA, wB Vec2    // support points on A and B
+	w      Vec2    // wB - wA
+	a      float64 // barycentric coordinate for the closest point
+}
+
+type simplex struct {
+	v     [3]simplexVertex
+	count int
+}
+
+func (s *simplex) searchDirection() Vec2 {
+	switch s.count {
+	case 1:
+		return s.v[0].w.Negative()
+	case 2:
+		e12 := s.v[1].w.Sub(s.v[0].w)
+		sgn := CrossVV(e12, s.v[0].w.Negative())
+		if sgn > 0.0 {
+			// Origin is left of e12.
+			return CrossSV(1.0, e12)
+		}
+		// Origin is right of e12.
+		return CrossVS(e12, 1.0)
+	}
+	return Vec2{0.0, 0.0}
+}
+
+func (s *simplex) closestPoint() Vec2 {
+	switch s.count {
+	case 1:
+		return s.v[0].w
+	case 2:
+		p := MulSV(s.v[0].a, s.v[0].w)
+		return p.Add(MulSV(s.v[1].a, s.v[1].w))
+	}
+	return Vec2{0.0, 0.0}
+}
+
+func (s *simplex) witnessPoints() (Vec2, Vec2) {
+	switch s.count {
+	case 1:
+		return s.v[0].wA, s.v[0].wB
+	case 2:
+		pA := MulSV(s.v[0].a, s.v[0].wA)
+		pA = pA.Add(MulSV(s.v[1].a, s.v[1].wA))
+		pB := MulSV(s.v[0].a, s.v[0].wB)
+		pB = pB.Add(MulSV(s.v[1].a, s.v[1].wB))
+		return pA, pB
+	}
+	pA := MulSV(s.v[0].a, s.v[0].wA)
+	pA = pA.Add(MulSV(s.v[1].a, s.v[1].wA))
+	pA = pA.Add(MulSV(s.v[2].a, s.v[2].wA))
+	return pA, pA
+}
+
+// Solve a line segment using barycentric coordinates.
+//
+// p = a1 * w1 + a2 * w2
+// a1 + a2 = 1
+//
+// The vector from the origin to the closest point on the line is
+// perpendicular to the line.
+// e12 = w2 - w1
+// dot(p, e) = 0
+// a1 * dot(w1, e) + a2 * dot(w2, e) = 0
+func (s *simplex) solve2() {
+	w1 := s.v[0].w
+	w2 := s.v[1].w
+	e12 := w2.Sub(w1)
+
+	// w1 region
+	d12n2 := -Dot(w1, e12)
+	if d12n2 <= 0.0 {
+		// a2 <= 0, so we clamp it to 0
+		s.v[0].a = 1.0
+		s.count = 1
+		return
+	}
+
+	// w2 region
+	d12n1 := Dot(w2, e12)
+	if d12n1 <= 0.0 {
+		// a1 <= 0, so we clamp it to 0
+		s.v[1].a = 1.0
+		s.v[0] = s.v[1]
+		s.count = 1
+		return
+	}
+
+	// Must be in e12 region.
+	inv := 1.0 / (d12n1 + d12n2)
+	s.v[0].a = d12n1 * inv
+	s.v[1].a = d12n2 * inv
+	s.count = 2
+}
+
+// Possible regions:
+// - points[2]
+// - edge points[0]-points[2]
+// - edge points[1]-points[2]
+// - inside the triangle
+func (s *simplex) solve3() {
+	w1 := s.v[0].w
+	w2 := s.v[1].w
+	w3 := s.v[2].w
+
+	// Edge12
+	e12 := w2.Sub(w1)
+	w1e12 := Dot(w1, e12)
+	w2e12 := Dot(w2, e12)
+	d12n1 := w2e12
+	d12n2 := -w1e12
+
+	// Edge13
+	e13 := w3.Sub(w1)
+	w1e13 := Dot(w1, e13)
+	w3e13 := Dot(w3, e13)
+	d13n1 := w3e13
+	d13n2 := -w1e13
+
+	// Edge23
+	e23 := w3.Sub(w2)
+	w2e23 := Dot(w2, e23)
+	w3e23 := Dot(w3, e23)
+	d23n1 := w3e23
+	d23n2 := -w2e23
+
+	// Triangle123
+	n123 := CrossVV(e12, e13)
+
+	d123n1 := n123 * CrossVV(w2, w3)
+	d123n2 := n123 * CrossVV(w3, w1)
+	d123n3 := n123 * CrossVV(w1, w2)
+
+	switch {
+	case d12n2 <= 0.0 && d13n2 <= 0.0:
+		// w1 region
+		s.v[0].a = 1.0
+		s.count = 1
+
+	case d12n1 > 0.0 && d12n2 > 0.0 && d123n3 <= 0.0:
+		// e12
+		inv := 1.0 / (d12n1 + d12n2)
+		s.v[0].a = d12n1 * inv
+		s.v[1].a = d12n2 * inv
+		s.count = 2
+
+	case d13n1 > 0.0 && d13n2 > 0.0 && d123n2 <= 0.0:
+		// e13
+		inv := 1.0 / (d13n1 + d13n2)
+		s.v[0].a = d13n1 * inv
+		s.v[2].a = d13n2 * inv
+		s.count = 2
+		s.v[1] = s.v[2]
+
+	case d12n1 <= 0.0 && d23n2 <= 0.0:
+		// w2 region
+		s.v[1].a = 1.0
+		s.count = 1
+		s.v[0] = s.v[1]
+
+	case d13n1 <= 0.0 && d23n1 <= 0.0:
+		// w3 region
+		s.v[2].a = 1.0
+		s.count = 1
+		s.v[0] = s.v[2]
+
+	case d23n1 > 0.0 && d23n2 > 0.0 && d123n1 <= 0.0:
+		// e23
+		inv := 1.0 / (d23n1 + d23n2)
+		s.v[1].a = d23n1 * inv
+		s.v[2].a = d23n2 * inv
+		s.count = 2
+		s.v[0] = s.v[2]
+
+	default:
+		// Must be in triangle123
+		inv := 1.0 / (d123n1 + d123n2 + d123n3)
+		s.v[0].a = d123n1 * inv
+		s.v[1].a = d123n2 * inv
+		s.v[2].a = d123n3 * inv
+		s.count = 3
+	}
+}
+
+// supportVertex returns the simplex vertex for the search direction d in world space.
+func supportVertex(proxyA distanceProxy, xfA Transform, proxyB distanceProxy, xfB Transform, d Vec2) simplexVertex {
+	var v simplexVertex
+	v.wA = xfA.MulV(proxyA.support(xfA.R.MulTV(d.Negative())))
+	v.wB = xfB.MulV(proxyB.support(xfB.R.MulTV(d)))
+	v.w = v.wB.Sub(v.wA)
+	return v
+}
+
+// gjk finds the closest points of two proxies.
+func gjk(proxyA distanceProxy, xfA Transform, proxyB distanceProxy, xfB Transform) DistanceOutput {
+	var s simplex
+	d := xfB.Position.Sub(xfA.Position)
+	if Dot(d, d) < gjkEpsilon*gjkEpsilon {
+		d = Vec2{1.0, 0.0}
+	}
+	s.v[0] = supportVertex(proxyA, xfA, proxyB, xfB, d)
+	s.v[0].a = 1.0
+	s.count = 1
+
+	iter := 0
+	for iter < gjkMaxIterations {
+		switch s.count {
+		case 2:
+			s.solve2()
+		case 3:
+			s.solve3()
+		}
+
+		// If we have 3 points, then the origin is in the corresponding triangle.
+		if s.count == 3 {
+			break
+		}
+
+		// The origin on a point or segment of the simplex means overlap.
+		p := s.closestPoint()
+		if Dot(p, p) < gjkEpsilon*gjkEpsilon {
+			break
+		}
+
+		// Get search direction.
+		d := s.searchDirection()
+
+		// Ensure the search direction is numerically fit.
+		if Dot(d, d) < gjkEpsilon*gjkEpsilon {
+			// The origin is probably contained by a line segment
+			// or triangle. Thus the shapes are overlapped.
+			break
+		}
+
+		// Compute a tentative new simplex vertex using support points.
+		v := supportVertex(proxyA, xfA, proxyB, xfB, d)
+		iter++
+
+		// Check for duplicate support points, and for no progress toward
+		// the origin. This is the main termination criteria.
+		duplicate := false
+		for i := 0; i < s.count; i++ {
+			if e := v.w.Sub(s.v[i].w); Dot(e, e) < gjkEpsilon*gjkEpsilon {
+				duplicate = true
+				break
+			}
+		}
+		dn := d.Normalize()
+		if duplicate || Dot(v.w, dn)-Dot(p, dn) < gjkEpsilon {
+			break
+		}
+
+		// New vertex is ok and needed.
+		s.v[s.count] = v
+		s.count++
+	}
+
+	var output DistanceOutput
+	output.PointA, output.PointB = s.witnessPoints()
+	e := output.PointB.Sub(output.PointA)
+	output.Distance = e.Length()
+	output.Iterations = iter
+
+	// Apply radii.
+	rA := proxyA.radius
+	rB := proxyB.radius
+	if output.Distance > rA+rB && output.Distance > gjkEpsilon {
+		// Shapes are still not overlapped.
+		// Move the witness points to the outer surface.
+		normal := MulSV(1.0/output.Distance, e)
+		output.Distance -= rA + rB
+		output.PointA = output.PointA.Add(MulSV(rA, normal))
+		output.PointB = output.PointB.Sub(MulSV(rB, normal))
+	} else {
+		// Shapes are overlapped when radii are considered.
+		// Move the witness points to the middle.
+		p := MulSV(0.5, output.PointA.Add(output.PointB))
+		output.PointA = p
+		output.PointB = p
+		output.Distance = 0.0
+	}
+
+	return output
+}
+
+const (
+	// Gap at which a shape cast counts as touching
+	castTolerance = 0.0025
+
+	castMaxIterations = 30
+)
+
+// castProxies finds the first time proxyB, placed at xfB and translated by
+// t * translation, comes within castTolerance of proxyA. It uses
+// conservative advancement: GJK gives the gap and its normal, and B moves
+// ahead by the gap over its speed along the normal, which cannot overshoot
+// convex shapes. It reports false if they do not meet before maxFraction.
+func castProxies(proxyA distanceProxy, xfA Transform, proxyB distanceProxy, xfB Transform, translation Vec2, maxFraction float64) (float64, DistanceOutput, bool) {
+	t := 0.0
+	var output DistanceOutput
+
+	for iter := 0; iter < castMaxIterations; iter++ {
+		xf := Transform{xfB.Position.Add(MulSV(t, translation)), xfB.R}
+		output = gjk(proxyA, xfA, proxyB, xf)
+		if output.Distance < castTolerance {
+			return t, output, true
+		}
+
+		// The normal points from A to B.
+		normal := output.PointB.Sub(output.PointA)
+		normal = MulSV(1.0/output.Distance, normal)
+
+		speed := -Dot(normal, translation)
+		if speed <= 0.0 {
+			return 0.0, output, false
+		}
+
+		// Stop short of touching, within the tolerance.
+		t += (output.Distance - 0.5*castTolerance) / speed
+		if t > maxFraction {
+			return 0.0, output, false
+		}
+	}
+
+	return t, output, true
+}
+
+// convexChildren returns the convex parts of s: the edges of a chain or
+// heightfield, or s itself.
+func convexChildren(s Shape) []Shape {
+	switch s := s.(type) {
+	case *Chain:
+		children := make([]Shape, s.ChildCount())
+		for i := range children {
+			children[i] = s.ChildEdge(i)
+		}
+		return children
+	case *Heightfield:
+		children := make([]Shape, s.ChildCount())
+		for i := range children {
+			children[i] = s.ChildEdge(i)
+		}
+		return children
+	}
+	return []Shape{s}
+}
+
+// ShapeDistance returns the closest points of shapeA placed at xfA and
+// shapeB placed at xfB. Chains and heightfields count as their edges.
+// Other shapes must be convex.
+func ShapeDistance(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) DistanceOutput {
+	output := DistanceOutput{Distance: math.MaxFloat64}
+	for _, a := range convexChildren(shapeA) {
+		proxyA := makeDistanceProxy(a)
+		for _, b := range convexChildren(shapeB) {
+			if o := gjk(proxyA, xfA, makeDistanceProxy(b), xfB); o.Distance < output.Distance {
+				output = o
+			}
+		}
+	}
+	return output
+}
+
+// Distance returns the closest points of two bodies, over all their
+// fixtures, and the distance between them. It is zero when they overlap.
+func Distance(bodyA, bodyB *Body) DistanceOutput {
+	output := DistanceOutput{Distance: math.MaxFloat64}
+	for _, fA := range bodyA.Fixtures {
+		for _, fB := range bodyB.Fixtures {
+			if o := ShapeDistance(fA.Shape, fA.Transform(), fB.Shape, fB.Transform()); o.Distance < output.Distance {
+				output = o
+			}
+		}
+	}
+	return output
+}
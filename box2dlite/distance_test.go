@@ -0,0 +1,90 @@
+package box2dlite
+
+import (
+	"math"
+	"testing"
+)
+
+func TestShapeDistance(t *testing.T) {
+	box := NewBox(Vec2{2.0, 2.0})
+	tests := []struct {
+		name           string
+		shapeA         Shape
+		xfA            Transform
+		shapeB         Shape
+		xfB            Transform
+		distance       float64
+		pointA, pointB Vec2
+	}{
+		{
+			name:   "separated boxes",
+			shapeA: box, xfA: NewTransform(Vec2{0.0, 0.0}, 0.0),
+			shapeB: box, xfB: NewTransform(Vec2{5.0, 2.5}, 0.0),
+			distance: math.Hypot(3.0, 0.5),
+			pointA:   Vec2{1.0, 1.0}, pointB: Vec2{4.0, 1.5},
+		},
+		{
+			name:   "separated circle and box",
+			shapeA: NewCircle(1.0), xfA: NewTransform(Vec2{0.0, 4.0}, 0.0),
+			shapeB: box, xfB: NewTransform(Vec2{0.0, 0.0}, 0.0),
+			distance: 2.0,
+			pointA:   Vec2{0.0, 3.0}, pointB: Vec2{0.0, 1.0},
+		},
+		{
+			name:   "separated rotated box",
+			shapeA: box, xfA: NewTransform(Vec2{0.0, 0.0}, math.Pi/4.0),
+			shapeB: box, xfB: NewTransform(Vec2{7.0, 0.0}, 0.0),
+			distance: 6.0 - math.Sqrt2,
+			pointA:   Vec2{math.Sqrt2, 0.0}, pointB: Vec2{6.0, 0.0},
+		},
+		{
+			name:   "overlapping boxes",
+			shapeA: box, xfA: NewTransform(Vec2{0.0, 0.0}, 0.0),
+			shapeB: box, xfB: NewTransform(Vec2{1.5, 0.5}, 0.3),
+			distance: 0.0,
+		},
+		{
+			name:   "overlapping circles",
+			shapeA: NewCircle(1.0), xfA: NewTransform(Vec2{0.0, 0.0}, 0.0),
+			shapeB: NewCircle(1.0), xfB: NewTransform(Vec2{1.0, 0.0}, 0.0),
+			distance: 0.0,
+		},
+	}
+
+	for _, tt := range tests {
+		output := ShapeDistance(tt.shapeA, tt.xfA, tt.shapeB, tt.xfB)
+		if math.Abs(output.Distance-tt.distance) > 1e-9 {
+			t.Errorf("%s: distance = %v, want %v", tt.name, output.Distance, tt.distance)
+		}
+		if output.Iterations >= gjkMaxIterations {
+			t.Errorf("%s: took %d iterations", tt.name, output.Iterations)
+		}
+
+		if tt.distance == 0.0 {
+			if output.PointA != output.PointB {
+				t.Errorf("%s: points %v and %v, want them to coincide", tt.name, output.PointA, output.PointB)
+			}
+			continue
+		}
+		if d := output.PointA.Sub(tt.pointA); d.Length() > 1e-9 {
+			t.Errorf("%s: point A = %v, want %v", tt.name, output.PointA, tt.pointA)
+		}
+		if d := output.PointB.Sub(tt.pointB); d.Length() > 1e-9 {
+			t.Errorf("%s: point B = %v, want %v", tt.name, output.PointB, tt.pointB)
+		}
+	}
+}
+
+func TestDistanceBodies(t *testing.T) {
+	a := &Body{}
+	a.AddFixture(NewCircle(0.5), Vec2{-3.0, 0.0}, 0.0, 1.0)
+	a.AddFixture(NewCircle(0.5), Vec2{3.0, 0.0}, 0.0, 1.0)
+
+	b := &Body{}
+	b.SetShape(NewBox(Vec2{1.0, 1.0}), 1.0)
+	b.Position = Vec2{5.0, 0.0}
+
+	if output := Distance(a, b); math.Abs(output.Distance-1.0) > 1e-9 {
+		t.Errorf("distance = %v, want 1 from the nearer fixture", output.Distance)
+	}
+}
@@ -0,0 +1,429 @@
+package box2dlite
+
+// The dynamic tree is the bounding volume hierarchy of Box2D: each leaf
+// holds a fat AABB, an AABB grown by a margin, so a proxy that moves a
+// little stays in place, and inner nodes hold the union of their children.
+// Leaves are inserted where they grow the tree's perimeter the least, and
+// the tree is kept balanced by rotations.
+// See https://box2d.org/files/ErinCatto_DynamicBVH_GDC2019.pdf
+
+const (
+	nullNode = -1
+)
+
+type treeNode struct {
+	aabb     AABB
+	userData interface{}
+
+	// parent, or the next free node in the free list
+	parent         int
+	child1, child2 int
+
+	// leaf = 0, free node = -1
+	height int
+}
+
+func (n *treeNode) isLeaf() bool {
+	return n.child1 == nullNode
+}
+
+// DynamicTree is an AABB tree of proxies. Proxies are identified by the id
+// CreateProxy returns, which stays valid until DestroyProxy.
+type DynamicTree struct {
+	nodes    []treeNode
+	root     int
+	freeList int
+}
+
+func NewDynamicTree() *DynamicTree {
+	return &DynamicTree{
+		root:     nullNode,
+		freeList: nullNode,
+	}
+}
+
+func (t *DynamicTree) allocateNode() int {
+	node := treeNode{
+		parent: nullNode,
+		child1: nullNode,
+		child2: nullNode,
+	}
+
+	if t.freeList != nullNode {
+		id := t.freeList
+		t.freeList = t.nodes[id].parent
+		t.nodes[id] = node
+		return id
+	}
+
+	t.nodes = append(t.nodes, node)
+	return len(t.nodes) - 1
+}
+
+func (t *DynamicTree) freeNode(id int) {
+	t.nodes[id] = treeNode{
+		parent: t.freeList,
+		child1: nullNode,
+		child2: nullNode,
+		height: -1,
+	}
+	t.freeList = id
+}
+
+// CreateProxy adds a proxy for aabb to the tree and returns its id.
+func (t *DynamicTree) CreateProxy(aabb AABB, userData interface{}) int {
+	id := t.allocateNode()
+
+	t.nodes[id].aabb = fattenAABB(aabb, Vec2{0.0, 0.0})
+	t.nodes[id].userData = userData
+
+	t.insertLeaf(id)
+	return id
+}
+
+// DestroyProxy removes a proxy from the tree.
+func (t *DynamicTree) DestroyProxy(id int) {
+	t.removeLeaf(id)
+	t.freeNode(id)
+}
+
+// MoveProxy updates a proxy for its new aabb after a move by displacement.
+// The proxy is only reinserted if aabb left its fat AABB, or if the fat AABB
+// became much too large; MoveProxy then reports true.
+func (t *DynamicTree) MoveProxy(id int, aabb AABB, displacement Vec2) bool {
+	fatAABB, ok := refattenAABB(t.nodes[id].aabb, aabb, displacement)
+	if !ok {
+		return false
+	}
+
+	t.removeLeaf(id)
+	t.nodes[id].aabb = fatAABB
+	t.insertLeaf(id)
+	return true
+}
+
+// UserData returns the data the proxy was created with.
+func (t *DynamicTree) UserData(id int) interface{} {
+	return t.nodes[id].userData
+}
+
+// FatAABB returns the fat AABB of a proxy.
+func (t *DynamicTree) FatAABB(id int) AABB {
+	return t.nodes[id].aabb
+}
+
+// Height returns the height of the tree, 0 for a single leaf.
+func (t *DynamicTree) Height() int {
+	if t.root == nullNode {
+		return 0
+	}
+	return t.nodes[t.root].height
+}
+
+// Query calls callback for each proxy whose fat AABB overlaps aabb, until
+// the callback returns false.
+func (t *DynamicTree) Query(aabb AABB, callback func(id int) bool) {
+	if t.root == nullNode {
+		return
+	}
+
+	stack := []int{t.root}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := &t.nodes[id]
+		if !node.aabb.Overlaps(aabb) {
+			continue
+		}
+
+		if node.isLeaf() {
+			if !callback(id) {
+				return
+			}
+		} else {
+			stack = append(stack, node.child1, node.child2)
+		}
+	}
+}
+
+// RayCast calls callback for each proxy whose fat AABB the ray may cross.
+// The callback gets the ray cut at the current fraction and returns the
+// fraction to cut it at from then on: 0 ends the cast, and a negative
+// value leaves the ray as it is.
+func (t *DynamicTree) RayCast(input RayCastInput, callback func(input RayCastInput, id int) float64) {
+	if t.root == nullNode {
+		return
+	}
+
+	maxFraction := input.MaxFraction
+
+	stack := []int{t.root}
+	for len(stack) > 0 {
+		id := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := &t.nodes[id]
+		if !segmentOverlapsAABB(input.P1, input.P2, maxFraction, node.aabb) {
+			continue
+		}
+
+		if node.isLeaf() {
+			subInput := RayCastInput{input.P1, input.P2, maxFraction}
+			value := callback(subInput, id)
+			if value == 0.0 {
+				// The client has terminated the ray cast.
+				return
+			}
+			if value > 0.0 {
+				maxFraction = value
+			}
+		} else {
+			stack = append(stack, node.child1, node.child2)
+		}
+	}
+}
+
+func (t *DynamicTree) insertLeaf(leaf int) {
+	if t.root == nullNode {
+		t.root = leaf
+		t.nodes[leaf].parent = nullNode
+		return
+	}
+
+	// Find the best sibling for this node
+	leafAABB := t.nodes[leaf].aabb
+	index := t.root
+	for !t.nodes[index].isLeaf() {
+		node := &t.nodes[index]
+		child1 := node.child1
+		child2 := node.child2
+
+		area := node.aabb.Perimeter()
+		combined := node.aabb.Combine(leafAABB)
+		combinedArea := combined.Perimeter()
+
+		// Cost of creating a new parent for this node and the new leaf
+		cost := 2.0 * combinedArea
+
+		// Minimum cost of pushing the leaf further down the tree
+		inheritanceCost := 2.0 * (combinedArea - area)
+
+		// Cost of descending into either child
+		cost1 := t.descendCost(child1, leafAABB) + inheritanceCost
+		cost2 := t.descendCost(child2, leafAABB) + inheritanceCost
+
+		// Descend according to the minimum cost.
+		if cost < cost1 && cost < cost2 {
+			break
+		}
+
+		if cost1 < cost2 {
+			index = child1
+		} else {
+			index = child2
+		}
+	}
+
+	sibling := index
+
+	// Create a new parent.
+	oldParent := t.nodes[sibling].parent
+	newParent := t.allocateNode()
+	t.nodes[newParent].parent = oldParent
+	t.nodes[newParent].aabb = leafAABB.Combine(t.nodes[sibling].aabb)
+	t.nodes[newParent].height = t.nodes[sibling].height + 1
+
+	if oldParent != nullNode {
+		// The sibling was not the root.
+		if t.nodes[oldParent].child1 == sibling {
+			t.nodes[oldParent].child1 = newParent
+		} else {
+			t.nodes[oldParent].child2 = newParent
+		}
+	} else {
+		// The sibling was the root.
+		t.root = newParent
+	}
+
+	t.nodes[newParent].child1 = sibling
+	t.nodes[newParent].child2 = leaf
+	t.nodes[sibling].parent = newParent
+	t.nodes[leaf].parent = newParent
+
+	// Walk back up the tree fixing heights and AABBs
+	t.refit(t.nodes[leaf].parent)
+}
+
+// descendCost returns the perimeter a subtree grows by when aabb is inserted into it.
+func (t *DynamicTree) descendCost(child int, aabb AABB) float64 {
+	node := &t.nodes[child]
+	combined := aabb.Combine(node.aabb)
+	if node.isLeaf() {
+		return combined.Perimeter()
+	}
+	return combined.Perimeter() - node.aabb.Perimeter()
+}
+
+func (t *DynamicTree) removeLeaf(leaf int) {
+	if leaf == t.root {
+		t.root = nullNode
+		return
+	}
+
+	parent := t.nodes[leaf].parent
+	grandParent := t.nodes[parent].parent
+	sibling := t.nodes[parent].child1
+	if sibling == leaf {
+		sibling = t.nodes[parent].child2
+	}
+
+	if grandParent != nullNode {
+		// Destroy the parent and connect the sibling to the grand parent.
+		if t.nodes[grandParent].child1 == parent {
+			t.nodes[grandParent].child1 = sibling
+		} else {
+			t.nodes[grandParent].child2 = sibling
+		}
+		t.nodes[sibling].parent = grandParent
+		t.freeNode(parent)
+
+		// Adjust ancestor bounds.
+		t.refit(grandParent)
+	} else {
+		t.root = sibling
+		t.nodes[sibling].parent = nullNode
+		t.freeNode(parent)
+	}
+}
+
+// refit balances the ancestors of a changed node, starting at index, and
+// recomputes their heights and AABBs.
+func (t *DynamicTree) refit(index int) {
+	for index != nullNode {
+		index = t.balance(index)
+
+		node := &t.nodes[index]
+		child1 := &t.nodes[node.child1]
+		child2 := &t.nodes[node.child2]
+
+		node.height = 1 + maxInt(child1.height, child2.height)
+		node.aabb = child1.aabb.Combine(child2.aabb)
+
+		index = node.parent
+	}
+}
+
+// balance performs a left or right rotation if node A is imbalanced.
+// It returns the new root index of the subtree.
+func (t *DynamicTree) balance(iA int) int {
+	A := &t.nodes[iA]
+	if A.isLeaf() || A.height < 2 {
+		return iA
+	}
+
+	iB := A.child1
+	iC := A.child2
+	B := &t.nodes[iB]
+	C := &t.nodes[iC]
+
+	balance := C.height - B.height
+
+	// Rotate C up
+	if balance > 1 {
+		iF := C.child1
+		iG := C.child2
+		F := &t.nodes[iF]
+		G := &t.nodes[iG]
+
+		// Swap A and C
+		C.child1 = iA
+		C.parent = A.parent
+		A.parent = iC
+
+		// A's old parent should point to C
+		t.replaceChild(C.parent, iA, iC)
+
+		// Rotate
+		if F.height > G.height {
+			C.child2 = iF
+			A.child2 = iG
+			G.parent = iA
+			A.aabb = B.aabb.Combine(G.aabb)
+			C.aabb = A.aabb.Combine(F.aabb)
+
+			A.height = 1 + maxInt(B.height, G.height)
+			C.height = 1 + maxInt(A.height, F.height)
+		} else {
+			C.child2 = iG
+			A.child2 = iF
+			F.parent = iA
+			A.aabb = B.aabb.Combine(F.aabb)
+			C.aabb = A.aabb.Combine(G.aabb)
+
+			A.height = 1 + maxInt(B.height, F.height)
+			C.height = 1 + maxInt(A.height, G.height)
+		}
+
+		return iC
+	}
+
+	// Rotate B up
+	if balance < -1 {
+		iD := B.child1
+		iE := B.child2
+		D := &t.nodes[iD]
+		E := &t.nodes[iE]
+
+		// Swap A and B
+		B.child1 = iA
+		B.parent = A.parent
+		A.parent = iB
+
+		// A's old parent should point to B
+		t.replaceChild(B.parent, iA, iB)
+
+		// Rotate
+		if D.height > E.height {
+			B.child2 = iD
+			A.child1 = iE
+			E.parent = iA
+			A.aabb = C.aabb.Combine(E.aabb)
+			B.aabb = A.aabb.Combine(D.aabb)
+
+			A.height = 1 + maxInt(C.height, E.height)
+			B.height = 1 + maxInt(A.height, D.height)
+		} else {
+			B.child2 = iE
+			A.child1 = iD
+			D.parent = iA
+			A.aabb = C.aabb.Combine(D.aabb)
+			B.aabb = A.aabb.Combine(E.aabb)
+
+			A.height = 1 + maxInt(C.height, D.height)
+			B.height = 1 + maxInt(A.height, E.height)
+		}
+
+		return iB
+	}
+
+	return iA
+}
+
+// replaceChild points parent, or the root if there is none, at newChild instead of oldChild.
+func (t *DynamicTree) replaceChild(parent, oldChild, newChild int) {
+	if parent == nullNode {
+		t.root = newChild
+	} else if t.nodes[parent].child1 == oldChild {
+		t.nodes[parent].child1 = newChild
+	} else {
+		t.nodes[parent].child2 = newChild
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
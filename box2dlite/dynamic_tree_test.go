@@ -0,0 +1,156 @@
+package box2dlite
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// validate checks the links, heights and bounding boxes below node and
+// adds the leaves found there to leaves.
+func (tree *DynamicTree) validate(t *testing.T, node int, leaves map[int]bool) {
+	n := &tree.nodes[node]
+	if n.isLeaf() {
+		if n.child2 != nullNode {
+			t.Errorf("leaf %d has child2 %d", node, n.child2)
+		}
+		if n.height != 0 {
+			t.Errorf("leaf %d has height %d", node, n.height)
+		}
+		leaves[node] = true
+		return
+	}
+
+	for _, child := range []int{n.child1, n.child2} {
+		if child < 0 || child >= len(tree.nodes) {
+			t.Fatalf("node %d has child %d out of range", node, child)
+		}
+		if p := tree.nodes[child].parent; p != node {
+			t.Errorf("child %d of node %d has parent %d", child, node, p)
+		}
+		if !n.aabb.Contains(tree.nodes[child].aabb) {
+			t.Errorf("node %d does not contain child %d", node, child)
+		}
+		tree.validate(t, child, leaves)
+	}
+
+	h1 := tree.nodes[n.child1].height
+	h2 := tree.nodes[n.child2].height
+	if n.height != 1+maxInt(h1, h2) {
+		t.Errorf("node %d has height %d, want %d", node, n.height, 1+maxInt(h1, h2))
+	}
+}
+
+func randomAABB(r *rand.Rand) AABB {
+	c := Vec2{r.Float64()*100.0 - 50.0, r.Float64()*100.0 - 50.0}
+	e := Vec2{0.1 + r.Float64()*2.0, 0.1 + r.Float64()*2.0}
+	return AABB{c.Sub(e), c.Add(e)}
+}
+
+func TestDynamicTreeInvariants(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tree := NewDynamicTree()
+
+	// Live proxies and their tight AABBs
+	proxies := make(map[int]AABB)
+	var ids []int
+
+	for i := 0; i < 5000; i++ {
+		switch op := r.Intn(10); {
+		case op < 4 || len(ids) == 0:
+			aabb := randomAABB(r)
+			id := tree.CreateProxy(aabb, i)
+			if _, ok := proxies[id]; ok {
+				t.Fatalf("CreateProxy returned live id %d", id)
+			}
+			proxies[id] = aabb
+			ids = append(ids, id)
+
+		case op < 8:
+			id := ids[r.Intn(len(ids))]
+			d := Vec2{r.Float64()*4.0 - 2.0, r.Float64()*4.0 - 2.0}
+			aabb := proxies[id]
+			aabb = AABB{aabb.Min.Add(d), aabb.Max.Add(d)}
+			tree.MoveProxy(id, aabb, d)
+			proxies[id] = aabb
+
+		default:
+			k := r.Intn(len(ids))
+			id := ids[k]
+			tree.DestroyProxy(id)
+			delete(proxies, id)
+			ids[k] = ids[len(ids)-1]
+			ids = ids[:len(ids)-1]
+		}
+	}
+
+	if tree.root == nullNode {
+		t.Fatal("tree is empty")
+	}
+	if p := tree.nodes[tree.root].parent; p != nullNode {
+		t.Errorf("root has parent %d", p)
+	}
+
+	leaves := make(map[int]bool)
+	tree.validate(t, tree.root, leaves)
+	if t.Failed() {
+		return
+	}
+
+	if len(leaves) != len(proxies) {
+		t.Errorf("tree has %d leaves, want %d", len(leaves), len(proxies))
+	}
+	for id, aabb := range proxies {
+		if !leaves[id] {
+			t.Errorf("proxy %d is not a leaf", id)
+			continue
+		}
+		if fat := tree.FatAABB(id); !fat.Contains(aabb) {
+			t.Errorf("fat AABB of proxy %d does not contain its AABB", id)
+		}
+	}
+
+	// Every node is in the tree or on the free list.
+	free := 0
+	for id := tree.freeList; id != nullNode; id = tree.nodes[id].parent {
+		free++
+	}
+	if inner := len(leaves) - 1; free+len(leaves)+inner != len(tree.nodes) {
+		t.Errorf("%d free, %d leaves and %d inner nodes, want %d nodes", free, len(leaves), inner, len(tree.nodes))
+	}
+
+	if limit := 2 * int(math.Ceil(math.Log2(float64(len(proxies))))); tree.Height() > limit {
+		t.Errorf("height %d for %d proxies, want at most %d", tree.Height(), len(proxies), limit)
+	}
+
+	// Queries match brute force over the fat AABBs.
+	for i := 0; i < 100; i++ {
+		aabb := randomAABB(r)
+		aabb = AABB{aabb.Min.Sub(Vec2{5.0, 5.0}), aabb.Max.Add(Vec2{5.0, 5.0})}
+
+		want := make(map[int]bool)
+		for id := range proxies {
+			if fat := tree.FatAABB(id); fat.Overlaps(aabb) {
+				want[id] = true
+			}
+		}
+
+		got := make(map[int]bool)
+		tree.Query(aabb, func(id int) bool {
+			if got[id] {
+				t.Errorf("query reported proxy %d twice", id)
+			}
+			got[id] = true
+			return true
+		})
+
+		if len(got) != len(want) {
+			t.Errorf("query found %d proxies, want %d", len(got), len(want))
+		}
+		for id := range want {
+			if !got[id] {
+				t.Errorf("query missed proxy %d", id)
+			}
+		}
+	}
+}
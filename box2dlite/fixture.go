@@ -0,0 +1,68 @@
+package box2dlite
+
+// Fixture attaches a shape to a body at a local position and angle.
+// Create fixtures with Body.AddFixture.
+type Fixture struct {
+	Shape    Shape
+	Position Vec2
+	Rotation float64
+
+	// Mass per unit area. Call Body.ResetMass after changing it.
+	Density float64
+
+	// Which fixtures this one collides with
+	Filter Filter
+
+	// A sensor detects the fixtures it overlaps without pushing them,
+	// see World.SensorEvents. Sensors do not detect each other.
+	Sensor bool
+
+	Body *Body
+}
+
+// Filter decides which fixtures collide, as in Box2D. Each fixture belongs
+// to the categories set in CategoryBits and collides with the categories set
+// in MaskBits, and two fixtures collide only if each accepts the other.
+//
+// GroupIndex overrides the bits for fixtures of the same group: a positive
+// group always collides with itself and a negative group never does.
+// Zero is no group.
+type Filter struct {
+	CategoryBits uint16
+	MaskBits     uint16
+	GroupIndex   int16
+}
+
+// DefaultFilter puts a fixture in the first category, colliding with all
+// categories and in no group.
+func DefaultFilter() Filter {
+	return Filter{
+		CategoryBits: 0x0001,
+		MaskBits:     0xFFFF,
+	}
+}
+
+// shouldCollide reports whether the filters of two fixtures let them collide.
+func shouldCollide(fA, fB *Fixture) bool {
+	a, b := fA.Filter, fB.Filter
+	if a.GroupIndex == b.GroupIndex && a.GroupIndex != 0 {
+		return a.GroupIndex > 0
+	}
+	return a.MaskBits&b.CategoryBits != 0 && a.CategoryBits&b.MaskBits != 0
+}
+
+// LocalTransform returns the placement of the shape in the body frame.
+func (f *Fixture) LocalTransform() Transform {
+	return NewTransform(f.Position, f.Rotation)
+}
+
+// Transform returns the placement of the shape in the world.
+func (f *Fixture) Transform() Transform {
+	xf := f.Body.Transform()
+	return xf.Mul(f.LocalTransform())
+}
+
+// AABB returns the world bounding box of the fixture's shape.
+func (f *Fixture) AABB() AABB {
+	return f.Shape.ComputeAABB(f.Transform())
+}
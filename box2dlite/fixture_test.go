@@ -0,0 +1,51 @@
+package box2dlite
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFilterRules(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Filter
+		want bool
+	}{
+		{"defaults", DefaultFilter(), DefaultFilter(), true},
+		{"masked out by one side", Filter{CategoryBits: 0x0002, MaskBits: 0xFFFD}, DefaultFilter(), true},
+		{"masked out by both", Filter{CategoryBits: 0x0002, MaskBits: 0xFFFD}, Filter{CategoryBits: 0x0002, MaskBits: 0xFFFD}, false},
+		{"one-way mask", Filter{CategoryBits: 0x0002, MaskBits: 0x0001}, Filter{CategoryBits: 0x0004, MaskBits: 0xFFFF}, false},
+		{"positive group beats masks", Filter{CategoryBits: 0x0002, MaskBits: 0x0000, GroupIndex: 3}, Filter{CategoryBits: 0x0002, MaskBits: 0x0000, GroupIndex: 3}, true},
+		{"negative group beats masks", Filter{CategoryBits: 0x0001, MaskBits: 0xFFFF, GroupIndex: -3}, Filter{CategoryBits: 0x0001, MaskBits: 0xFFFF, GroupIndex: -3}, false},
+		{"different groups use masks", Filter{CategoryBits: 0x0001, MaskBits: 0xFFFF, GroupIndex: -3}, Filter{CategoryBits: 0x0001, MaskBits: 0xFFFF, GroupIndex: -4}, true},
+	}
+
+	for _, tt := range tests {
+		fA := &Fixture{Filter: tt.a}
+		fB := &Fixture{Filter: tt.b}
+		if got := shouldCollide(fA, fB); got != tt.want {
+			t.Errorf("%s: shouldCollide = %v, want %v", tt.name, got, tt.want)
+		}
+		if got := shouldCollide(fB, fA); got != tt.want {
+			t.Errorf("%s: shouldCollide swapped = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFilteredBodiesPassThrough(t *testing.T) {
+	w := NewWorld(Vec2{0.0, -10.0}, 10)
+	ground := addBody(w, NewBox(Vec2{20.0, 1.0}), math.MaxFloat64, Vec2{0.0, -0.5})
+	ground.SetFilter(Filter{CategoryBits: 0x0002, MaskBits: 0xFFFF})
+	ghost := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{0.0, 1.0})
+	ghost.SetFilter(Filter{CategoryBits: 0x0001, MaskBits: 0xFFFF &^ 0x0002})
+	box := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{3.0, 1.0})
+
+	stepWorld(w, 60)
+
+	if ghost.Position.Y > -1.0 {
+		t.Errorf("filtered box at y = %v, want it through the ground", ghost.Position.Y)
+	}
+	if math.Abs(box.Position.Y-0.5) > 0.05 {
+		t.Errorf("box at y = %v, want it resting on the ground at 0.5", box.Position.Y)
+	}
+}
@@ -33,8 +33,8 @@ func (j *Joint) PreStep(inv_dt float64) {
 	rot1 := Mat22ByAngle(j.Body1.Rotation)
 	rot2 := Mat22ByAngle(j.Body2.Rotation)
 
-	j.R1 = rot1.MulV(j.LocalAnchor1)
-	j.R2 = rot2.MulV(j.LocalAnchor2)
+	j.R1 = rot1.MulV(j.LocalAnchor1.Sub(j.Body1.LocalCenter))
+	j.R2 = rot2.MulV(j.LocalAnchor2.Sub(j.Body2.LocalCenter))
 
 	// deltaV = deltaV0 + K * impulse
 	// invM = [(1/m1 + 1/m2) * eye(2) - skew(r1) * invI1 * skew(r1) - skew(r2) * invI2 * skew(r2)]
@@ -62,8 +62,10 @@ func (j *Joint) PreStep(inv_dt float64) {
 
 	j.M = k.Invert()
 
-	p1 := j.Body1.Position.Add(j.R1)
-	p2 := j.Body2.Position.Add(j.R2)
+	p1 := j.Body1.WorldCenter()
+	p1 = p1.Add(j.R1)
+	p2 := j.Body2.WorldCenter()
+	p2 = p2.Add(j.R2)
 	dp := p2.Sub(p1)
 
 	if positionCorrection {
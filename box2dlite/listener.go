@@ -0,0 +1,26 @@
+package box2dlite
+
+// ContactListener hears about the arbiters of a world as they come and go
+// and as they are solved. The callbacks run inside World.Step, or for
+// EndContact also inside World.Clear, World.SetBroadPhase and
+// World.Refilter, and must not add or remove bodies. Queries such as
+// World.RayCast leave the arbiters to Step, so they never call it.
+type ContactListener interface {
+	// BeginContact is called when two fixtures start touching and their
+	// arbiter is created.
+	BeginContact(a *Arbiter)
+
+	// EndContact is called when two fixtures stop touching, or one leaves
+	// the world or the world is cleared, and their arbiter is deleted.
+	EndContact(a *Arbiter)
+
+	// PreSolve is called each step for each arbiter before it is solved.
+	// It may change a.Friction, which keeps the new value, or clear
+	// a.Enabled to skip the arbiter for this step.
+	PreSolve(a *Arbiter)
+
+	// PostSolve is called each step for each enabled arbiter after the
+	// solver iterations, with the normal and tangent impulses of the step
+	// accumulated in the Pn and Pt of its contacts.
+	PostSolve(a *Arbiter)
+}
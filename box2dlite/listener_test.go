@@ -0,0 +1,126 @@
+package box2dlite
+
+import (
+	"math"
+	"testing"
+)
+
+// recordingListener counts the callbacks and can disable arbiters.
+type recordingListener struct {
+	begin, end, preSolve, postSolve int
+
+	// disable makes PreSolve disable every arbiter.
+	disable bool
+}
+
+func (l *recordingListener) BeginContact(a *Arbiter) { l.begin++ }
+func (l *recordingListener) EndContact(a *Arbiter)   { l.end++ }
+
+func (l *recordingListener) PreSolve(a *Arbiter) {
+	l.preSolve++
+	if l.disable {
+		a.Enabled = false
+	}
+}
+
+func (l *recordingListener) PostSolve(a *Arbiter) { l.postSolve++ }
+
+func TestContactListenerCallbacks(t *testing.T) {
+	l := &recordingListener{}
+	w := NewWorld(Vec2{0.0, -10.0}, 10)
+	w.ContactListener = l
+	addBody(w, NewBox(Vec2{20.0, 1.0}), math.MaxFloat64, Vec2{0.0, -0.5})
+	box := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{0.0, 0.5})
+
+	stepWorld(w, 10)
+
+	if l.begin != 1 || l.end != 0 {
+		t.Errorf("%d begun and %d ended, want 1 and 0", l.begin, l.end)
+	}
+	if l.preSolve != 10 || l.postSolve != 10 {
+		t.Errorf("%d pre-solves and %d post-solves, want 10 each", l.preSolve, l.postSolve)
+	}
+
+	// Lift the box off the ground.
+	box.Position = Vec2{0.0, 5.0}
+	box.Velocity = Vec2{0.0, 0.0}
+	stepWorld(w, 1)
+	if l.end != 1 {
+		t.Errorf("%d ended after the box left the ground, want 1", l.end)
+	}
+}
+
+func TestPreSolveDisables(t *testing.T) {
+	l := &recordingListener{disable: true}
+	w := NewWorld(Vec2{0.0, -10.0}, 10)
+	w.ContactListener = l
+	addBody(w, NewBox(Vec2{20.0, 1.0}), math.MaxFloat64, Vec2{0.0, -0.5})
+	box := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{0.0, 0.5})
+
+	stepWorld(w, 30)
+
+	if box.Position.Y > 0.0 {
+		t.Errorf("box at y = %v, want it sinking through the disabled contact", box.Position.Y)
+	}
+	if l.postSolve != 0 {
+		t.Errorf("%d post-solves, want none for disabled arbiters", l.postSolve)
+	}
+}
+
+func TestClearEndsContacts(t *testing.T) {
+	l := &recordingListener{}
+	w := NewWorld(Vec2{0.0, -10.0}, 10)
+	w.ContactListener = l
+	addBody(w, NewBox(Vec2{20.0, 1.0}), math.MaxFloat64, Vec2{0.0, -0.5})
+	addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{0.0, 0.5})
+	stepWorld(w, 10)
+
+	w.Clear()
+	if l.end != 1 {
+		t.Errorf("%d ended by Clear, want 1", l.end)
+	}
+	if len(w.Arbiters) != 0 {
+		t.Errorf("%d arbiters left after Clear", len(w.Arbiters))
+	}
+}
+
+func TestSetBroadPhaseEndsContacts(t *testing.T) {
+	l := &recordingListener{}
+	w := NewWorld(Vec2{0.0, -10.0}, 10)
+	w.ContactListener = l
+	addBody(w, NewBox(Vec2{20.0, 1.0}), math.MaxFloat64, Vec2{0.0, -0.5})
+	addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{0.0, 0.5})
+	stepWorld(w, 10)
+
+	w.SetBroadPhase(NewSweepAndPruneBroadPhase())
+	if l.end != 1 {
+		t.Errorf("%d ended by SetBroadPhase, want 1", l.end)
+	}
+
+	// The contact starts again under the new broad-phase.
+	stepWorld(w, 1)
+	if l.begin != 2 {
+		t.Errorf("%d begun after SetBroadPhase, want 2", l.begin)
+	}
+}
+
+func TestQueriesDoNotEndContacts(t *testing.T) {
+	l := &recordingListener{}
+	w := NewWorld(Vec2{0.0, -10.0}, 10)
+	w.ContactListener = l
+	addBody(w, NewBox(Vec2{20.0, 1.0}), math.MaxFloat64, Vec2{0.0, -0.5})
+	box := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{0.0, 0.5})
+	stepWorld(w, 10)
+
+	removeBody(w, box)
+	w.RayCastClosest(Vec2{-5.0, 0.5}, Vec2{5.0, 0.5})
+	w.QueryPoint(Vec2{0.0, 0.5})
+	if l.end != 0 {
+		t.Errorf("%d ended by queries, want none outside Step", l.end)
+	}
+
+	stepWorld(w, 1)
+	if l.end != 1 {
+		t.Errorf("%d ended by Step, want 1", l.end)
+	}
+}
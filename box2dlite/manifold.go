@@ -0,0 +1,78 @@
+package box2dlite
+
+// ManifoldPoint is a contact point between two shapes.
+type ManifoldPoint struct {
+	// World point between the two surfaces
+	Position Vec2
+
+	// Unit vector from A to B
+	Normal Vec2
+
+	// Negative when the shapes overlap
+	Separation float64
+
+	// Ids of the features of A and B that meet here
+	Feature FeaturePair
+}
+
+// Manifold is the contact between two shapes.
+//
+// Convex shapes touch along one normal, shared by all the points. The
+// segments of a chain or heightfield each have their own, so points on
+// different segments carry different normals; Normal is then the normal
+// of the deepest point.
+type Manifold struct {
+	Normal Vec2
+	Points []ManifoldPoint
+}
+
+// CollideShapes returns the manifold of shapeA placed at xfA and shapeB
+// placed at xfB, with no points when they do not touch. It runs the
+// narrow-phase of the simulation without any bodies or world, so it can
+// test hypothetical placements. Shape pairs without a collide function,
+// built in or registered with RegisterCollideFunc, never touch.
+//
+// The normal points from A to B
+func CollideShapes(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) Manifold {
+	var m Manifold
+
+	contacts := collideShapes(shapeA, xfA, shapeB, xfB)
+	if len(contacts) == 0 {
+		return m
+	}
+
+	m.Points = make([]ManifoldPoint, len(contacts))
+	deepest := contacts[0]
+	for i, c := range contacts {
+		m.Points[i] = ManifoldPoint{
+			Position:   c.Position,
+			Normal:     c.Normal,
+			Separation: c.Separation,
+			Feature:    c.Feature,
+		}
+		if c.Separation < deepest.Separation {
+			deepest = c
+		}
+	}
+	m.Normal = deepest.Normal
+
+	return m
+}
+
+// Contacts returns the points of the manifold as solver contacts.
+func (m *Manifold) Contacts() []*Contact {
+	if len(m.Points) == 0 {
+		return nil
+	}
+
+	contacts := make([]*Contact, len(m.Points))
+	for i, p := range m.Points {
+		contacts[i] = &Contact{
+			Position:   p.Position,
+			Normal:     p.Normal,
+			Separation: p.Separation,
+			Feature:    p.Feature,
+		}
+	}
+	return contacts
+}
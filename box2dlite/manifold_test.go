@@ -0,0 +1,52 @@
+package box2dlite
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCollideShapesBoxOnBox(t *testing.T) {
+	ground := NewBox(Vec2{4.0, 1.0})
+	box := NewBox(Vec2{1.0, 1.0})
+
+	m := CollideShapes(ground, NewTransform(Vec2{0.0, 0.0}, 0.0), box, NewTransform(Vec2{0.0, 0.9}, 0.0))
+	if len(m.Points) != 2 {
+		t.Fatalf("manifold has %d points, want 2", len(m.Points))
+	}
+	if !near(m.Normal, Vec2{0.0, 1.0}) {
+		t.Errorf("normal = %v, want (0, 1) from the ground to the box", m.Normal)
+	}
+	for _, p := range m.Points {
+		if math.Abs(p.Separation+0.1) > 1e-9 {
+			t.Errorf("separation = %v, want -0.1", p.Separation)
+		}
+		if p.Normal != m.Normal {
+			t.Errorf("point normal %v differs from %v", p.Normal, m.Normal)
+		}
+	}
+
+	if m := CollideShapes(ground, NewTransform(Vec2{0.0, 0.0}, 0.0), box, NewTransform(Vec2{0.0, 2.0}, 0.0)); len(m.Points) != 0 {
+		t.Errorf("apart shapes have %d points", len(m.Points))
+	}
+}
+
+func TestCollideMatchesCollideShapes(t *testing.T) {
+	a := &Body{}
+	a.SetShape(NewBox(Vec2{4.0, 1.0}), math.MaxFloat64)
+	b := &Body{}
+	b.SetShape(NewCircle(0.5), 1.0)
+	b.Position = Vec2{1.0, 0.9}
+	b.Rotation = 0.3
+
+	contacts := Collide(a, b)
+	m := CollideShapes(a.Fixtures[0].Shape, a.Transform(), b.Fixtures[0].Shape, b.Transform())
+	if len(contacts) != len(m.Points) {
+		t.Fatalf("Collide found %d contacts, CollideShapes %d points", len(contacts), len(m.Points))
+	}
+	for i, c := range contacts {
+		p := m.Points[i]
+		if c.Position != p.Position || c.Normal != p.Normal || c.Separation != p.Separation || c.Feature != p.Feature {
+			t.Errorf("contact %d is %+v, point is %+v", i, *c, p)
+		}
+	}
+}
@@ -55,6 +55,15 @@ func (v *Vec2) Length() float64 {
 	return math.Hypot(v.X, v.Y)
 }
 
+// Normalize returns the unit vector along v, or the zero vector if v has no length.
+func (v *Vec2) Normalize() Vec2 {
+	l := v.Length()
+	if l == 0.0 {
+		return Vec2{0.0, 0.0}
+	}
+	return MulSV(1.0/l, *v)
+}
+
 // Mat22
 
 type Mat22 struct {
@@ -104,6 +113,13 @@ func (m *Mat22) MulV(v Vec2) Vec2 {
 	}
 }
 
+func (m *Mat22) MulTV(v Vec2) Vec2 {
+	return Vec2{
+		m.Col1.X*v.X + m.Col1.Y*v.Y,
+		m.Col2.X*v.X + m.Col2.Y*v.Y,
+	}
+}
+
 func (m *Mat22) MulM(o Mat22) Mat22 {
 	return Mat22{
 		m.MulV(o.Col1), m.MulV(o.Col2),
@@ -116,6 +132,48 @@ func (m *Mat22) Abs() Mat22 {
 	}
 }
 
+// Transform
+
+// Transform is a rigid placement: a translation followed by a rotation.
+type Transform struct {
+	Position Vec2
+	R        Mat22
+}
+
+func NewTransform(position Vec2, angle float64) Transform {
+	return Transform{
+		position,
+		Mat22ByAngle(angle),
+	}
+}
+
+// MulV maps a point from local space to world space.
+func (t *Transform) MulV(v Vec2) Vec2 {
+	return t.Position.Add(t.R.MulV(v))
+}
+
+// MulTV maps a point from world space to local space.
+func (t *Transform) MulTV(v Vec2) Vec2 {
+	return t.R.MulTV(v.Sub(t.Position))
+}
+
+// Mul places o, given in t's local space, in the space t is given in.
+func (t *Transform) Mul(o Transform) Transform {
+	return Transform{
+		t.MulV(o.Position),
+		t.R.MulM(o.R),
+	}
+}
+
+// MulT returns o relative to t, mapping o's local space to t's local space.
+func (t *Transform) MulT(o Transform) Transform {
+	RT := t.R.Transpose()
+	return Transform{
+		RT.MulV(o.Position.Sub(t.Position)),
+		RT.MulM(o.R),
+	}
+}
+
 // functions
 
 func Dot(a, b Vec2) float64 {
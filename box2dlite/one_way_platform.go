@@ -0,0 +1,119 @@
+package box2dlite
+
+// Largest upward speed, relative to the platform, a body can land with
+const oneWayLandingSpeed = 0.1
+
+// Smallest cosine between the contact normal and the platform's up for
+// a landing
+const oneWayLandingCos = 0.5
+
+// OneWayPlatform is a ContactListener that makes fixtures into one-way
+// platforms: bodies land on them from above and pass through them from
+// below and from the sides.
+//
+// Whether a body lands is decided when it starts touching a platform, from
+// the contact normal and its velocity relative to the platform. A body that
+// passes keeps passing until it stops touching, so it cannot get caught
+// halfway through. The arbiter is only disabled, so it keeps its contacts
+// for warm starting.
+//
+// Set it as the World.ContactListener. Other callbacks go on to Next.
+type OneWayPlatform struct {
+	// Direction bodies land from, in the platform body's frame
+	Up Vec2
+
+	// Listener that gets every callback after the platform, if set
+	Next ContactListener
+
+	platforms map[*Fixture]bool
+	passing   map[*Arbiter]bool
+}
+
+// NewOneWayPlatform makes a listener for platforms landed on from the up direction.
+func NewOneWayPlatform(up Vec2) *OneWayPlatform {
+	return &OneWayPlatform{
+		Up:        up.Normalize(),
+		platforms: make(map[*Fixture]bool),
+		passing:   make(map[*Arbiter]bool),
+	}
+}
+
+// Add makes f a one-way platform.
+func (p *OneWayPlatform) Add(f *Fixture) {
+	p.platforms[f] = true
+}
+
+// Remove makes f solid again once the bodies passing through it leave.
+func (p *OneWayPlatform) Remove(f *Fixture) {
+	delete(p.platforms, f)
+}
+
+func (p *OneWayPlatform) BeginContact(a *Arbiter) {
+	if p.passes(a) {
+		p.passing[a] = true
+	}
+	if p.Next != nil {
+		p.Next.BeginContact(a)
+	}
+}
+
+func (p *OneWayPlatform) EndContact(a *Arbiter) {
+	delete(p.passing, a)
+	if p.Next != nil {
+		p.Next.EndContact(a)
+	}
+}
+
+func (p *OneWayPlatform) PreSolve(a *Arbiter) {
+	if p.passing[a] {
+		a.Enabled = false
+	}
+	if p.Next != nil {
+		p.Next.PreSolve(a)
+	}
+}
+
+func (p *OneWayPlatform) PostSolve(a *Arbiter) {
+	if p.Next != nil {
+		p.Next.PostSolve(a)
+	}
+}
+
+// passes reports whether the body touching a platform through a moves
+// through it rather than landing.
+func (p *OneWayPlatform) passes(a *Arbiter) bool {
+	// Normal from the platform to the other body
+	var platform, other *Body
+	sign := 1.0
+	switch {
+	case p.platforms[a.Fixture1]:
+		platform, other = a.Body1, a.Body2
+	case p.platforms[a.Fixture2]:
+		platform, other = a.Body2, a.Body1
+		sign = -1.0
+	default:
+		return false
+	}
+
+	R := Mat22ByAngle(platform.Rotation)
+	up := R.MulV(p.Up)
+
+	for _, c := range a.Contacts {
+		n := MulSV(sign, c.Normal)
+		if Dot(n, up) < oneWayLandingCos {
+			return true
+		}
+
+		// Relative velocity at contact
+		r1 := c.Position.Sub(platform.WorldCenter())
+		r2 := c.Position.Sub(other.WorldCenter())
+		dv := other.Velocity.Add(CrossSV(other.AngularVelocity, r2))
+		dv = dv.Sub(platform.Velocity)
+		dv = dv.Sub(CrossSV(platform.AngularVelocity, r1))
+		if Dot(dv, up) > oneWayLandingSpeed {
+			return true
+		}
+	}
+
+	return false
+}
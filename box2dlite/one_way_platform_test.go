@@ -0,0 +1,54 @@
+package box2dlite
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOneWayPlatform(t *testing.T) {
+	w := NewWorld(Vec2{0.0, -10.0}, 10)
+	platforms := NewOneWayPlatform(Vec2{0.0, 1.0})
+	w.ContactListener = platforms
+
+	platform := addBody(w, NewBox(Vec2{4.0, 0.2}), math.MaxFloat64, Vec2{0.0, 0.0})
+	platforms.Add(platform.Fixtures[0])
+
+	// One box falls onto the platform, the other jumps up through it.
+	faller := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{-1.0, 2.0})
+	jumper := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{1.0, -2.0})
+	jumper.Velocity = Vec2{0.0, 10.0}
+
+	highest := jumper.Position.Y
+	for i := 0; i < 180; i++ {
+		w.Step(1.0 / 60.0)
+		highest = math.Max(highest, jumper.Position.Y)
+	}
+
+	if highest < 2.5 {
+		t.Errorf("jumper rose to y = %v, want it through the platform near 3", highest)
+	}
+	if math.Abs(faller.Position.Y-0.6) > 0.05 {
+		t.Errorf("faller at y = %v, want it on the platform at 0.6", faller.Position.Y)
+	}
+	if math.Abs(jumper.Position.Y-0.6) > 0.05 {
+		t.Errorf("jumper at y = %v, want it landed back on the platform at 0.6", jumper.Position.Y)
+	}
+}
+
+func TestOneWayPlatformRemove(t *testing.T) {
+	w := NewWorld(Vec2{0.0, -10.0}, 10)
+	platforms := NewOneWayPlatform(Vec2{0.0, 1.0})
+	w.ContactListener = platforms
+
+	platform := addBody(w, NewBox(Vec2{4.0, 0.2}), math.MaxFloat64, Vec2{0.0, 0.0})
+	platforms.Add(platform.Fixtures[0])
+	platforms.Remove(platform.Fixtures[0])
+
+	jumper := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{0.0, -2.0})
+	jumper.Velocity = Vec2{0.0, 10.0}
+	stepWorld(w, 60)
+
+	if jumper.Position.Y > -0.5 {
+		t.Errorf("jumper at y = %v, want it stopped under the solid platform", jumper.Position.Y)
+	}
+}
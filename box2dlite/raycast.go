@@ -0,0 +1,183 @@
+package box2dlite
+
+import (
+	"math"
+)
+
+// RayCastInput is the ray from P1 toward P2, cut at MaxFraction of the way.
+type RayCastInput struct {
+	P1, P2      Vec2
+	MaxFraction float64
+}
+
+// RayCastOutput is where a ray enters a shape: the surface normal and the
+// fraction of the way from P1 to P2.
+type RayCastOutput struct {
+	Normal   Vec2
+	Fraction float64
+}
+
+// segmentAABB returns the bounds of the ray cut at maxFraction.
+func segmentAABB(p1, p2 Vec2, maxFraction float64) AABB {
+	d := p2.Sub(p1)
+	t := p1.Add(MulSV(maxFraction, d))
+	return AABB{
+		Vec2{math.Min(p1.X, t.X), math.Min(p1.Y, t.Y)},
+		Vec2{math.Max(p1.X, t.X), math.Max(p1.Y, t.Y)},
+	}
+}
+
+// segmentOverlapsAABB reports whether the ray cut at maxFraction may cross
+// aabb. It tests the bounds of the ray and the axis normal to it.
+func segmentOverlapsAABB(p1, p2 Vec2, maxFraction float64, aabb AABB) bool {
+	bounds := segmentAABB(p1, p2, maxFraction)
+	if !bounds.Overlaps(aabb) {
+		return false
+	}
+
+	// Separating axis for the segment: |dot(v, p1 - c)| > dot(|v|, h)
+	r := p2.Sub(p1)
+	r = r.Normalize()
+	v := CrossSV(1.0, r)
+	absV := v.Abs()
+
+	c := aabb.Center()
+	h := aabb.Extents()
+	separation := math.Abs(Dot(v, p1.Sub(c))) - Dot(absV, h)
+	return separation <= 0.0
+}
+
+// rayCastCircle casts the ray p1 + t * d, t in [0, maxFraction], against
+// a circle. Rays starting inside the circle do not hit it.
+func rayCastCircle(p1, d Vec2, maxFraction float64, center Vec2, radius float64) (RayCastOutput, bool) {
+	s := p1.Sub(center)
+	b := Dot(s, s) - radius*radius
+
+	// Solve quadratic equation.
+	c := Dot(s, d)
+	rr := Dot(d, d)
+	sigma := c*c - rr*b
+
+	// Check for negative discriminant and short segment.
+	if sigma < 0.0 || rr < 1.0e-12 {
+		return RayCastOutput{}, false
+	}
+
+	// Find the point of intersection of the line with the circle.
+	a := -(c + math.Sqrt(sigma))
+
+	// Is the intersection point on the segment?
+	if 0.0 <= a && a <= maxFraction*rr {
+		a /= rr
+		n := s.Add(MulSV(a, d))
+		return RayCastOutput{Normal: n.Normalize(), Fraction: a}, true
+	}
+
+	return RayCastOutput{}, false
+}
+
+// rayCastPolygon casts a ray against a polygon placed at xf, rounded by its radius.
+// Rays starting inside the polygon do not hit it.
+func rayCastPolygon(poly *Polygon, input RayCastInput, xf Transform) (RayCastOutput, bool) {
+	// Put the ray into the polygon's frame of reference.
+	p1 := xf.MulTV(input.P1)
+	p2 := xf.MulTV(input.P2)
+	d := p2.Sub(p1)
+
+	if poly.Radius > 0.0 {
+		return rayCastRoundedPolygon(poly, p1, d, input.MaxFraction, xf)
+	}
+
+	lower, upper := 0.0, input.MaxFraction
+	index := -1
+
+	for i, n := range poly.Normals {
+		// p = p1 + a * d
+		// dot(normal, p - v) = 0
+		// dot(normal, p1 - v) + a * dot(normal, d) = 0
+		numerator := Dot(n, poly.Vertices[i].Sub(p1))
+		denominator := Dot(n, d)
+
+		if denominator == 0.0 {
+			if numerator < 0.0 {
+				return RayCastOutput{}, false
+			}
+		} else {
+			// Note: we want this predicate without division:
+			// lower < numerator / denominator, where denominator < 0
+			// Since denominator < 0, we have to flip the inequality:
+			// lower < numerator / denominator <==> denominator * lower > numerator.
+			if denominator < 0.0 && numerator < lower*denominator {
+				// Increase lower.
+				// The segment enters this half-space.
+				lower = numerator / denominator
+				index = i
+			} else if denominator > 0.0 && numerator < upper*denominator {
+				// Decrease upper.
+				// The segment exits this half-space.
+				upper = numerator / denominator
+			}
+		}
+
+		if upper < lower {
+			return RayCastOutput{}, false
+		}
+	}
+
+	if index < 0 {
+		return RayCastOutput{}, false
+	}
+
+	return RayCastOutput{Normal: xf.R.MulV(poly.Normals[index]), Fraction: lower}, true
+}
+
+// rayCastRoundedPolygon casts the local ray p1 + t * d against the faces of
+// the polygon pushed out by its radius and the circles rounding its vertices.
+func rayCastRoundedPolygon(poly *Polygon, p1, d Vec2, maxFraction float64, xf Transform) (RayCastOutput, bool) {
+	r := poly.Radius
+	n := len(poly.Vertices)
+
+	// Rays starting inside do not hit.
+	if poly.containsLocal(p1) {
+		return RayCastOutput{}, false
+	}
+
+	var output RayCastOutput
+	hit := false
+
+	for i, normal := range poly.Normals {
+		denominator := Dot(normal, d)
+		if denominator >= 0.0 {
+			continue
+		}
+
+		a := poly.Vertices[i].Add(MulSV(r, normal))
+		b := poly.Vertices[(i+1)%n].Add(MulSV(r, normal))
+
+		t := Dot(normal, a.Sub(p1)) / denominator
+		if t < 0.0 || t > maxFraction {
+			continue
+		}
+
+		q := p1.Add(MulSV(t, d))
+		e := b.Sub(a)
+		if s := Dot(q.Sub(a), e); s < 0.0 || s > Dot(e, e) {
+			continue
+		}
+
+		maxFraction = t
+		output = RayCastOutput{Normal: normal, Fraction: t}
+		hit = true
+	}
+
+	for _, v := range poly.Vertices {
+		if out, ok := rayCastCircle(p1, d, maxFraction, v, r); ok {
+			maxFraction = out.Fraction
+			output = out
+			hit = true
+		}
+	}
+
+	output.Normal = xf.R.MulV(output.Normal)
+	return output, hit
+}
@@ -0,0 +1,43 @@
+package box2dlite
+
+// SensorEvent is a sensor fixture starting or ending to overlap the
+// fixture of another body.
+type SensorEvent struct {
+	Sensor  *Fixture
+	Visitor *Fixture
+}
+
+// SensorEvents returns the overlaps of sensors that began and ended since
+// it was last called, including those ended by removing a body from the
+// world and those ended between steps by Refilter, Clear or
+// SetBroadPhase. Events are kept until they are handed out, so call it
+// after each step.
+func (w *World) SensorEvents() (begin, end []SensorEvent) {
+	begin, end = w.sensorBegin, w.sensorEnd
+	w.sensorBegin, w.sensorEnd = nil, nil
+	return begin, end
+}
+
+// sensorEvent returns the event of a pair with a sensor.
+func sensorEvent(key ArbiterKey) SensorEvent {
+	if key.Fixture1.Sensor {
+		return SensorEvent{Sensor: key.Fixture1, Visitor: key.Fixture2}
+	}
+	return SensorEvent{Sensor: key.Fixture2, Visitor: key.Fixture1}
+}
+
+// setSensorOverlap records whether the fixtures of a pair overlap, and
+// reports the change.
+func (w *World) setSensorOverlap(key ArbiterKey, overlapping bool) {
+	if w.sensors[key] == overlapping {
+		return
+	}
+
+	if overlapping {
+		w.sensors[key] = true
+		w.sensorBegin = append(w.sensorBegin, sensorEvent(key))
+	} else {
+		delete(w.sensors, key)
+		w.sensorEnd = append(w.sensorEnd, sensorEvent(key))
+	}
+}
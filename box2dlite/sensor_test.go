@@ -0,0 +1,91 @@
+package box2dlite
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSensorEvents(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	zone := addBody(w, NewBox(Vec2{4.0, 4.0}), math.MaxFloat64, Vec2{0.0, 0.0})
+	zone.Fixtures[0].Sensor = true
+	box := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{-4.0, 0.0})
+	box.Velocity = Vec2{6.0, 0.0}
+
+	var began, ended []SensorEvent
+	for i := 0; i < 120; i++ {
+		w.Step(1.0 / 60.0)
+		begin, end := w.SensorEvents()
+		began = append(began, begin...)
+		ended = append(ended, end...)
+
+		if len(begin) > 0 && math.Abs(box.Position.X+2.5) > 0.2 {
+			t.Errorf("overlap began with the box at x = %v, want -2.5", box.Position.X)
+		}
+	}
+
+	if len(began) != 1 || len(ended) != 1 {
+		t.Fatalf("%d began and %d ended, want 1 and 1", len(began), len(ended))
+	}
+	if began[0].Sensor != zone.Fixtures[0] || began[0].Visitor != box.Fixtures[0] {
+		t.Errorf("event %+v, want the zone sensing the box", began[0])
+	}
+	// Sensors do not push back.
+	if box.Velocity.X != 6.0 {
+		t.Errorf("box velocity x = %v, want 6 through the sensor", box.Velocity.X)
+	}
+}
+
+func TestSensorEventsHandedOutOnce(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	zone := addBody(w, NewBox(Vec2{4.0, 4.0}), math.MaxFloat64, Vec2{0.0, 0.0})
+	zone.Fixtures[0].Sensor = true
+	addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{0.0, 0.0})
+
+	stepWorld(w, 2)
+
+	if begin, end := w.SensorEvents(); len(begin) != 1 || len(end) != 0 {
+		t.Fatalf("%d began and %d ended, want 1 and 0", len(begin), len(end))
+	}
+	if begin, end := w.SensorEvents(); len(begin) != 0 || len(end) != 0 {
+		t.Errorf("%d began and %d ended on the second read, want none", len(begin), len(end))
+	}
+}
+
+func TestSensorEventsKeepRefilterEnd(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	zone := addBody(w, NewBox(Vec2{4.0, 4.0}), math.MaxFloat64, Vec2{0.0, 0.0})
+	zone.Fixtures[0].Sensor = true
+	box := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{0.0, 0.0})
+	stepWorld(w, 1)
+	w.SensorEvents()
+
+	box.SetFilter(Filter{CategoryBits: 0x0002, MaskBits: 0x0000})
+	w.Refilter(box)
+	stepWorld(w, 1)
+
+	if begin, end := w.SensorEvents(); len(begin) != 0 || len(end) != 1 {
+		t.Errorf("%d began and %d ended, want the overlap ended by Refilter", len(begin), len(end))
+	}
+}
+
+func TestSensorEventsOnRemovedBody(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	zone := addBody(w, NewBox(Vec2{4.0, 4.0}), math.MaxFloat64, Vec2{0.0, 0.0})
+	zone.Fixtures[0].Sensor = true
+	box := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{0.0, 0.0})
+	stepWorld(w, 1)
+	w.SensorEvents()
+
+	removeBody(w, box)
+	// Queries leave the end to the step.
+	w.QueryPoint(Vec2{0.0, 0.0})
+	if _, end := w.SensorEvents(); len(end) != 0 {
+		t.Errorf("QueryPoint ended %d overlaps, want none before Step", len(end))
+	}
+
+	stepWorld(w, 1)
+	if _, end := w.SensorEvents(); len(end) != 1 || end[0].Visitor != box.Fixtures[0] {
+		t.Errorf("ended %v, want the removed box", end)
+	}
+}
@@ -0,0 +1,50 @@
+package box2dlite
+
+type ShapeType uint8
+
+const (
+	SHAPE_BOX ShapeType = iota
+	SHAPE_CIRCLE
+	SHAPE_POLYGON
+	SHAPE_CAPSULE
+	SHAPE_EDGE
+	SHAPE_CHAIN
+	SHAPE_HEIGHTFIELD
+
+	// SHAPE_USER is the first type free for custom shapes, see RegisterCollideFunc.
+	SHAPE_USER ShapeType = 128
+)
+
+// MassData holds the mass properties of a shape.
+// I is the rotational inertia about Center.
+type MassData struct {
+	Mass   float64
+	Center Vec2
+	I      float64
+}
+
+// Shape is the collision geometry attached to a Body.
+// Shapes are defined in the body's local frame and placed in the world by a Transform.
+//
+// Custom shapes must implement every method, as the engine uses them all.
+// The interface may gain methods as the engine does, and custom shapes then
+// need them too.
+type Shape interface {
+	Type() ShapeType
+
+	// ComputeAABB returns the bounding box of the shape placed at xf.
+	ComputeAABB(xf Transform) AABB
+
+	// ComputeMass returns the mass properties of the shape for the given density.
+	ComputeMass(density float64) MassData
+
+	// Support returns the local point of the shape farthest along the local direction d.
+	Support(d Vec2) Vec2
+
+	// TestPoint reports whether the shape placed at xf contains the world point p.
+	TestPoint(p Vec2, xf Transform) bool
+
+	// RayCast returns where the ray enters the shape placed at xf.
+	// Rays starting inside the shape do not hit it.
+	RayCast(input RayCastInput, xf Transform) (RayCastOutput, bool)
+}
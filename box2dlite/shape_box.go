@@ -0,0 +1,68 @@
+package box2dlite
+
+import (
+	"math"
+)
+
+// Box is an oriented rectangle centered at the body origin.
+//
+// A positive Radius rounds the corners: the box grows by Radius on every
+// side and collides against the rounded outline.
+type Box struct {
+	Width  Vec2
+	Radius float64
+}
+
+func NewBox(w Vec2) *Box {
+	return &Box{Width: w}
+}
+
+// NewRoundedBox makes a box of width w rounded by radius.
+func NewRoundedBox(w Vec2, radius float64) *Box {
+	return &Box{Width: w, Radius: radius}
+}
+
+func (s *Box) Type() ShapeType {
+	return SHAPE_BOX
+}
+
+func (s *Box) ComputeAABB(xf Transform) AABB {
+	h := MulSV(0.5, s.Width)
+	absR := xf.R.Abs()
+	e := absR.MulV(h)
+	e = e.Add(Vec2{s.Radius, s.Radius})
+	return AABB{
+		xf.Position.Sub(e),
+		xf.Position.Add(e),
+	}
+}
+
+func (s *Box) ComputeMass(density float64) MassData {
+	if s.Radius > 0.0 {
+		return boxPolygon(s).ComputeMass(density)
+	}
+
+	w := s.Width
+	m := density * w.X * w.Y
+	return MassData{
+		Mass: m,
+		I:    m * (w.X*w.X + w.Y*w.Y) / 12.0,
+	}
+}
+
+func (s *Box) Support(d Vec2) Vec2 {
+	h := MulSV(0.5, s.Width)
+	v := Vec2{
+		math.Copysign(h.X, d.X),
+		math.Copysign(h.Y, d.Y),
+	}
+	return v.Add(MulSV(s.Radius, d.Normalize()))
+}
+
+func (s *Box) TestPoint(p Vec2, xf Transform) bool {
+	return boxPolygon(s).TestPoint(p, xf)
+}
+
+func (s *Box) RayCast(input RayCastInput, xf Transform) (RayCastOutput, bool) {
+	return rayCastPolygon(boxPolygon(s), input, xf)
+}
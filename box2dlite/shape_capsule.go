@@ -0,0 +1,96 @@
+package box2dlite
+
+import (
+	"math"
+)
+
+// Capsule is the segment from Center1 to Center2 swept by a circle of Radius.
+type Capsule struct {
+	Center1, Center2 Vec2
+	Radius           float64
+}
+
+// NewCapsule makes a capsule. It panics if the two centers coincide; use a Circle instead.
+func NewCapsule(c1, c2 Vec2, r float64) *Capsule {
+	if c1 == c2 {
+		panic("NewCapsule fails because the centers coincide")
+	}
+	return &Capsule{
+		Center1: c1,
+		Center2: c2,
+		Radius:  r,
+	}
+}
+
+func (s *Capsule) Type() ShapeType {
+	return SHAPE_CAPSULE
+}
+
+func (s *Capsule) ComputeAABB(xf Transform) AABB {
+	v1 := xf.MulV(s.Center1)
+	v2 := xf.MulV(s.Center2)
+	r := Vec2{s.Radius, s.Radius}
+	lower := Vec2{math.Min(v1.X, v2.X), math.Min(v1.Y, v2.Y)}
+	upper := Vec2{math.Max(v1.X, v2.X), math.Max(v1.Y, v2.Y)}
+	return AABB{
+		lower.Sub(r),
+		upper.Add(r),
+	}
+}
+
+func (s *Capsule) ComputeMass(density float64) MassData {
+	r := s.Radius
+	rr := r * r
+	d := s.Center2.Sub(s.Center1)
+	length := d.Length()
+	ll := length * length
+
+	circleMass := density * Pi * rr
+	boxMass := density * 2.0 * r * length
+
+	// The two half discs sit at the ends of the box. Their centroids are
+	// lc from the box ends, so the parallel axis theorem is applied twice:
+	// m * ((h + lc)^2 - lc^2) = m * (h^2 + 2 * h * lc)
+	lc := 4.0 * r / (3.0 * Pi)
+	h := 0.5 * length
+
+	circleInertia := circleMass * (0.5*rr + h*h + 2.0*h*lc)
+	boxInertia := boxMass * (4.0*rr + ll) / 12.0
+
+	return MassData{
+		Mass:   circleMass + boxMass,
+		Center: MulSV(0.5, s.Center1.Add(s.Center2)),
+		I:      circleInertia + boxInertia,
+	}
+}
+
+func (s *Capsule) Support(d Vec2) Vec2 {
+	c := s.Center1
+	if Dot(s.Center2, d) > Dot(s.Center1, d) {
+		c = s.Center2
+	}
+	return c.Add(MulSV(s.Radius, d.Normalize()))
+}
+
+func (s *Capsule) TestPoint(p Vec2, xf Transform) bool {
+	q := xf.MulTV(p)
+	c, _, _, _ := segmentDistance(s.Center1, s.Center2, q, q)
+	d := q.Sub(c)
+	return Dot(d, d) <= s.Radius*s.Radius
+}
+
+func (s *Capsule) RayCast(input RayCastInput, xf Transform) (RayCastOutput, bool) {
+	return rayCastPolygon(capsulePolygon(s), input, xf)
+}
+
+// capsulePolygon returns the capsule as a two sided polygon rounded by its radius.
+func capsulePolygon(capsule *Capsule) *Polygon {
+	e := capsule.Center2.Sub(capsule.Center1)
+	n := CrossVS(e, 1.0)
+	n = n.Normalize()
+	return &Polygon{
+		Vertices: []Vec2{capsule.Center1, capsule.Center2},
+		Normals:  []Vec2{n, n.Negative()},
+		Radius:   capsule.Radius,
+	}
+}
@@ -0,0 +1,146 @@
+package box2dlite
+
+import (
+	"math"
+)
+
+// Chain is a polyline of one-sided edges, either open or closed into a loop.
+// As with polygons, the edges collide on their right side: a loop wound
+// counter-clockwise is solid inside, one wound clockwise holds bodies inside.
+// Ground facing up therefore lists its vertices from right to left.
+//
+// PrevVertex and NextVertex are the ghost vertices beyond the ends of an
+// open chain. Contact features number the segments in order and wrap around
+// every 256 segments.
+//
+// Chains have no area, so they belong on static bodies.
+type Chain struct {
+	Vertices               []Vec2
+	PrevVertex, NextVertex Vec2
+	Loop                   bool
+}
+
+// NewChain makes an open chain. The ghost vertices continue the end segments in a straight line.
+func NewChain(vertices []Vec2) *Chain {
+	n := len(vertices)
+	if n < 2 {
+		panic("NewChain fails because of the vertex count")
+	}
+
+	s := &Chain{
+		Vertices: make([]Vec2, n),
+	}
+	copy(s.Vertices, vertices)
+
+	s.PrevVertex = MulSV(2.0, vertices[0])
+	s.PrevVertex = s.PrevVertex.Sub(vertices[1])
+	s.NextVertex = MulSV(2.0, vertices[n-1])
+	s.NextVertex = s.NextVertex.Sub(vertices[n-2])
+	return s
+}
+
+// NewLoop makes a closed chain. The last vertex connects back to the first one.
+func NewLoop(vertices []Vec2) *Chain {
+	if len(vertices) < 3 {
+		panic("NewLoop fails because of the vertex count")
+	}
+
+	s := &Chain{
+		Vertices: make([]Vec2, len(vertices)),
+		Loop:     true,
+	}
+	copy(s.Vertices, vertices)
+	return s
+}
+
+func (s *Chain) Type() ShapeType {
+	return SHAPE_CHAIN
+}
+
+func (s *Chain) ComputeAABB(xf Transform) AABB {
+	lower := xf.MulV(s.Vertices[0])
+	upper := lower
+	for _, v := range s.Vertices[1:] {
+		v = xf.MulV(v)
+		lower = Vec2{math.Min(lower.X, v.X), math.Min(lower.Y, v.Y)}
+		upper = Vec2{math.Max(upper.X, v.X), math.Max(upper.Y, v.Y)}
+	}
+	return AABB{lower, upper}
+}
+
+func (s *Chain) ComputeMass(density float64) MassData {
+	return MassData{}
+}
+
+func (s *Chain) Support(d Vec2) Vec2 {
+	best := s.Vertices[0]
+	for _, v := range s.Vertices[1:] {
+		if Dot(v, d) > Dot(best, d) {
+			best = v
+		}
+	}
+	return best
+}
+
+// TestPoint is always false: chains have no area.
+func (s *Chain) TestPoint(p Vec2, xf Transform) bool {
+	return false
+}
+
+// RayCast returns the first edge of the chain the ray hits.
+func (s *Chain) RayCast(input RayCastInput, xf Transform) (RayCastOutput, bool) {
+	var output RayCastOutput
+	hit := false
+
+	for i := 0; i < s.ChildCount(); i++ {
+		if out, ok := s.ChildEdge(i).RayCast(input, xf); ok {
+			input.MaxFraction = out.Fraction
+			output = out
+			hit = true
+		}
+	}
+
+	return output, hit
+}
+
+// ChildCount returns the number of edges in the chain.
+func (s *Chain) ChildCount() int {
+	if s.Loop {
+		return len(s.Vertices)
+	}
+	return len(s.Vertices) - 1
+}
+
+// ChildEdge returns edge i of the chain, with the ghost vertices of its neighbors.
+func (s *Chain) ChildEdge(i int) *Edge {
+	n := len(s.Vertices)
+
+	e := &Edge{
+		Vertex1:  s.Vertices[i],
+		Vertex2:  s.Vertices[(i+1)%n],
+		OneSided: true,
+		index:    i,
+	}
+
+	if s.Loop {
+		e.loop = n
+	}
+
+	if i > 0 {
+		e.Vertex0 = s.Vertices[i-1]
+	} else if s.Loop {
+		e.Vertex0 = s.Vertices[n-1]
+	} else {
+		e.Vertex0 = s.PrevVertex
+	}
+
+	if i+2 < n {
+		e.Vertex3 = s.Vertices[i+2]
+	} else if s.Loop {
+		e.Vertex3 = s.Vertices[(i+2)%n]
+	} else {
+		e.Vertex3 = s.NextVertex
+	}
+
+	return e
+}
@@ -0,0 +1,44 @@
+package box2dlite
+
+// Circle is a disc centered at the body origin.
+type Circle struct {
+	Radius float64
+}
+
+func NewCircle(r float64) *Circle {
+	return &Circle{Radius: r}
+}
+
+func (s *Circle) Type() ShapeType {
+	return SHAPE_CIRCLE
+}
+
+func (s *Circle) ComputeAABB(xf Transform) AABB {
+	r := Vec2{s.Radius, s.Radius}
+	return AABB{
+		xf.Position.Sub(r),
+		xf.Position.Add(r),
+	}
+}
+
+func (s *Circle) ComputeMass(density float64) MassData {
+	m := density * Pi * s.Radius * s.Radius
+	return MassData{
+		Mass: m,
+		I:    0.5 * m * s.Radius * s.Radius,
+	}
+}
+
+func (s *Circle) Support(d Vec2) Vec2 {
+	return MulSV(s.Radius, d.Normalize())
+}
+
+func (s *Circle) TestPoint(p Vec2, xf Transform) bool {
+	d := p.Sub(xf.Position)
+	return Dot(d, d) <= s.Radius*s.Radius
+}
+
+func (s *Circle) RayCast(input RayCastInput, xf Transform) (RayCastOutput, bool) {
+	d := input.P2.Sub(input.P1)
+	return rayCastCircle(input.P1, d, input.MaxFraction, xf.Position, s.Radius)
+}
@@ -0,0 +1,142 @@
+package box2dlite
+
+import (
+	"math"
+)
+
+// Edge is a line segment from Vertex1 to Vertex2.
+//
+// A one-sided edge only collides on its right side, the side its outward
+// normal points to, as with the edges of a counter-clockwise polygon.
+// Vertex0 and Vertex3 are the ghost vertices of the neighboring segments:
+// they let shapes slide across the joints of a terrain without catching on
+// its internal corners.
+//
+// Edges have no area, so they belong on static bodies.
+type Edge struct {
+	Vertex0, Vertex1, Vertex2, Vertex3 Vec2
+	OneSided                           bool
+
+	// index of the edge in its chain and the edge count of a loop,
+	// which offset and wrap its feature numbers
+	index, loop int
+}
+
+// NewEdge makes a two-sided edge.
+func NewEdge(v1, v2 Vec2) *Edge {
+	return &Edge{
+		Vertex1: v1,
+		Vertex2: v2,
+	}
+}
+
+// NewOneSidedEdge makes a one-sided edge from v1 to v2 between the ghost vertices v0 and v3.
+func NewOneSidedEdge(v0, v1, v2, v3 Vec2) *Edge {
+	return &Edge{
+		Vertex0:  v0,
+		Vertex1:  v1,
+		Vertex2:  v2,
+		Vertex3:  v3,
+		OneSided: true,
+	}
+}
+
+func (s *Edge) Type() ShapeType {
+	return SHAPE_EDGE
+}
+
+func (s *Edge) ComputeAABB(xf Transform) AABB {
+	v1 := xf.MulV(s.Vertex1)
+	v2 := xf.MulV(s.Vertex2)
+	return AABB{
+		Vec2{math.Min(v1.X, v2.X), math.Min(v1.Y, v2.Y)},
+		Vec2{math.Max(v1.X, v2.X), math.Max(v1.Y, v2.Y)},
+	}
+}
+
+func (s *Edge) ComputeMass(density float64) MassData {
+	return MassData{
+		Center: MulSV(0.5, s.Vertex1.Add(s.Vertex2)),
+	}
+}
+
+func (s *Edge) Support(d Vec2) Vec2 {
+	if Dot(s.Vertex2, d) > Dot(s.Vertex1, d) {
+		return s.Vertex2
+	}
+	return s.Vertex1
+}
+
+// TestPoint is always false: edges have no area.
+func (s *Edge) TestPoint(p Vec2, xf Transform) bool {
+	return false
+}
+
+// RayCast hits a one-sided edge only from its front side, and a two-sided
+// edge from either side with the normal facing the ray.
+func (s *Edge) RayCast(input RayCastInput, xf Transform) (RayCastOutput, bool) {
+	// Put the ray into the edge's frame of reference.
+	p1 := xf.MulTV(input.P1)
+	p2 := xf.MulTV(input.P2)
+	d := p2.Sub(p1)
+
+	v1 := s.Vertex1
+	v2 := s.Vertex2
+	e := v2.Sub(v1)
+
+	// Normal points to the right, looking from v1 at v2
+	normal := CrossVS(e, 1.0)
+	normal = normal.Normalize()
+
+	// q = p1 + t * d
+	// dot(normal, q - v1) = 0
+	// dot(normal, p1 - v1) + t * dot(normal, d) = 0
+	numerator := Dot(normal, v1.Sub(p1))
+	if s.OneSided && numerator > 0.0 {
+		return RayCastOutput{}, false
+	}
+
+	denominator := Dot(normal, d)
+	if denominator == 0.0 {
+		return RayCastOutput{}, false
+	}
+
+	t := numerator / denominator
+	if t < 0.0 || input.MaxFraction < t {
+		return RayCastOutput{}, false
+	}
+
+	q := p1.Add(MulSV(t, d))
+
+	// q = v1 + u * e
+	ee := Dot(e, e)
+	if ee == 0.0 {
+		return RayCastOutput{}, false
+	}
+
+	if u := Dot(q.Sub(v1), e) / ee; u < 0.0 || 1.0 < u {
+		return RayCastOutput{}, false
+	}
+
+	if numerator > 0.0 {
+		normal = normal.Negative()
+	}
+
+	return RayCastOutput{Normal: xf.R.MulV(normal), Fraction: t}, true
+}
+
+// edgeNumber returns the feature number of the segment k places after this
+// one in its chain. Segments are numbered like the edges of a polygon, so
+// neighboring segments agree on the features of their common vertex.
+func (s *Edge) edgeNumber(k int) EdgeNumbers {
+	i := s.index + k
+	if s.loop > 0 {
+		i = (i + s.loop) % s.loop
+	}
+	return EdgeNumbers(i + 1)
+}
+
+// vertexFeature returns the feature of Vertex1 (i == 0) or Vertex2 (i == 1).
+func (s *Edge) vertexFeature(i int) (EdgeNumbers, EdgeNumbers) {
+	return s.edgeNumber(i - 1), s.edgeNumber(i)
+}
@@ -0,0 +1,155 @@
+package box2dlite
+
+import (
+	"math"
+)
+
+// Heightfield is terrain sampled at evenly spaced heights. Sample i sits at
+// (i * Spacing, Heights[i]) in the body frame, and the surface collides from
+// above like a chain of one-sided edges. Only the cells under a shape's
+// bounding box are collided, so long terrain stays cheap.
+//
+// Contact features number the cells from right to left and wrap around every
+// 256 cells.
+//
+// Heightfields have no area, so they belong on static bodies.
+type Heightfield struct {
+	Heights []float64
+	Spacing float64
+}
+
+// NewHeightfield makes a heightfield from at least two samples spacing apart.
+func NewHeightfield(heights []float64, spacing float64) *Heightfield {
+	if len(heights) < 2 {
+		panic("NewHeightfield fails because of the sample count")
+	}
+	if spacing <= 0.0 {
+		panic("NewHeightfield fails because the spacing is not positive")
+	}
+
+	s := &Heightfield{
+		Heights: make([]float64, len(heights)),
+		Spacing: spacing,
+	}
+	copy(s.Heights, heights)
+	return s
+}
+
+func (s *Heightfield) Type() ShapeType {
+	return SHAPE_HEIGHTFIELD
+}
+
+func (s *Heightfield) ComputeAABB(xf Transform) AABB {
+	minY, maxY := s.Heights[0], s.Heights[0]
+	for _, h := range s.Heights[1:] {
+		minY = math.Min(minY, h)
+		maxY = math.Max(maxY, h)
+	}
+	width := s.Spacing * float64(len(s.Heights)-1)
+
+	corners := [4]Vec2{{0.0, minY}, {width, minY}, {width, maxY}, {0.0, maxY}}
+	lower := xf.MulV(corners[0])
+	upper := lower
+	for _, v := range corners[1:] {
+		v = xf.MulV(v)
+		lower = Vec2{math.Min(lower.X, v.X), math.Min(lower.Y, v.Y)}
+		upper = Vec2{math.Max(upper.X, v.X), math.Max(upper.Y, v.Y)}
+	}
+	return AABB{lower, upper}
+}
+
+func (s *Heightfield) ComputeMass(density float64) MassData {
+	return MassData{}
+}
+
+func (s *Heightfield) Support(d Vec2) Vec2 {
+	best := s.Vertex(0)
+	for i := 1; i < len(s.Heights); i++ {
+		if v := s.Vertex(i); Dot(v, d) > Dot(best, d) {
+			best = v
+		}
+	}
+	return best
+}
+
+// TestPoint is always false: heightfields have no area.
+func (s *Heightfield) TestPoint(p Vec2, xf Transform) bool {
+	return false
+}
+
+// RayCast returns the first cell of the heightfield the ray hits from above.
+func (s *Heightfield) RayCast(input RayCastInput, xf Transform) (RayCastOutput, bool) {
+	// Cells under the ray in the heightfield's frame.
+	p1 := xf.MulTV(input.P1)
+	p2 := xf.MulTV(input.P2)
+	bounds := segmentAABB(p1, p2, input.MaxFraction)
+	i1, i2 := s.cellRange(bounds.Min.X, bounds.Max.X)
+
+	var output RayCastOutput
+	hit := false
+
+	for i := i1; i <= i2; i++ {
+		if out, ok := s.ChildEdge(i).RayCast(input, xf); ok {
+			input.MaxFraction = out.Fraction
+			output = out
+			hit = true
+		}
+	}
+
+	return output, hit
+}
+
+// Vertex returns sample i in the body frame.
+func (s *Heightfield) Vertex(i int) Vec2 {
+	return Vec2{s.Spacing * float64(i), s.Heights[i]}
+}
+
+// ChildCount returns the number of cells in the heightfield.
+func (s *Heightfield) ChildCount() int {
+	return len(s.Heights) - 1
+}
+
+// ChildEdge returns the surface of cell i as a one-sided edge facing up.
+// The terrain continues in a straight line beyond its ends.
+func (s *Heightfield) ChildEdge(i int) *Edge {
+	n := len(s.Heights)
+
+	// Run from right to left so the edge normal points up, and number the
+	// cells the same way so neighbors agree on the features of their joint.
+	e := &Edge{
+		Vertex1:  s.Vertex(i + 1),
+		Vertex2:  s.Vertex(i),
+		OneSided: true,
+		index:    n - 2 - i,
+	}
+
+	if i+2 < n {
+		e.Vertex0 = s.Vertex(i + 2)
+	} else {
+		e.Vertex0 = MulSV(2.0, e.Vertex1)
+		e.Vertex0 = e.Vertex0.Sub(e.Vertex2)
+	}
+
+	if i > 0 {
+		e.Vertex3 = s.Vertex(i - 1)
+	} else {
+		e.Vertex3 = MulSV(2.0, e.Vertex2)
+		e.Vertex3 = e.Vertex3.Sub(e.Vertex1)
+	}
+
+	return e
+}
+
+// cellRange returns the first and last cells overlapping the local x range [lower, upper].
+func (s *Heightfield) cellRange(lower, upper float64) (int, int) {
+	last := len(s.Heights) - 2
+	i1 := int(math.Floor(lower / s.Spacing))
+	i2 := int(math.Floor(upper / s.Spacing))
+	if i1 < 0 {
+		i1 = 0
+	}
+	if i2 > last {
+		i2 = last
+	}
+	return i1, i2
+}
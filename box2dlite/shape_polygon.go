@@ -0,0 +1,218 @@
+package box2dlite
+
+import (
+	"math"
+)
+
+const (
+	MaxPolygonVertices = 8
+)
+
+// Polygon is a convex polygon in the body's local frame.
+//
+// Edge i runs from Vertices[i] to Vertices[i+1] and is numbered i+1 in
+// contact features, so a polygon listing a box's corners counter-clockwise
+// from the top right one gets the same edge numbers as a Box.
+//
+// A positive Radius rounds the polygon: it grows by Radius on every side
+// and collides against the rounded outline.
+type Polygon struct {
+	Vertices []Vec2
+	Normals  []Vec2
+	Radius   float64
+}
+
+// NewPolygon makes a polygon from convex vertices in counter-clockwise order.
+// It panics if the vertices do not form such a polygon.
+func NewPolygon(vertices []Vec2) *Polygon {
+	n := len(vertices)
+	if n < 3 || n > MaxPolygonVertices {
+		panic("NewPolygon fails because of the vertex count")
+	}
+
+	p := &Polygon{
+		Vertices: make([]Vec2, n),
+		Normals:  make([]Vec2, n),
+	}
+	copy(p.Vertices, vertices)
+
+	for i := 0; i < n; i++ {
+		v1 := p.Vertices[i]
+		v2 := p.Vertices[(i+1)%n]
+		v3 := p.Vertices[(i+2)%n]
+
+		e1 := v2.Sub(v1)
+		e2 := v3.Sub(v2)
+		if CrossVV(e1, e2) <= 0.0 {
+			panic("NewPolygon fails because the vertices are not convex and counter-clockwise")
+		}
+
+		p.Normals[i] = CrossVS(e1, 1.0)
+		p.Normals[i] = p.Normals[i].Normalize()
+	}
+
+	return p
+}
+
+// NewRoundedPolygon makes a polygon from convex vertices in counter-clockwise order, rounded by radius.
+func NewRoundedPolygon(vertices []Vec2, radius float64) *Polygon {
+	p := NewPolygon(vertices)
+	p.Radius = radius
+	return p
+}
+
+func (s *Polygon) Type() ShapeType {
+	return SHAPE_POLYGON
+}
+
+func (s *Polygon) ComputeAABB(xf Transform) AABB {
+	lower := xf.MulV(s.Vertices[0])
+	upper := lower
+	for _, v := range s.Vertices[1:] {
+		v = xf.MulV(v)
+		lower = Vec2{math.Min(lower.X, v.X), math.Min(lower.Y, v.Y)}
+		upper = Vec2{math.Max(upper.X, v.X), math.Max(upper.Y, v.Y)}
+	}
+	r := Vec2{s.Radius, s.Radius}
+	return AABB{lower.Sub(r), upper.Add(r)}
+}
+
+func (s *Polygon) ComputeMass(density float64) MassData {
+	// Sum the triangles fanned out from the first vertex. Using a vertex
+	// inside the polygon as the origin keeps the round-off small.
+	origin := s.Vertices[0]
+	n := len(s.Vertices)
+
+	// Area, first moment and polar moment about origin
+	var area, I float64
+	var moment Vec2
+	for i := 1; i < n-1; i++ {
+		e1 := s.Vertices[i].Sub(origin)
+		e2 := s.Vertices[i+1].Sub(origin)
+
+		D := CrossVV(e1, e2)
+		triangleArea := 0.5 * D
+		area += triangleArea
+
+		// Area weighted centroid
+		moment = moment.Add(MulSV(triangleArea/3.0, e1.Add(e2)))
+
+		intx2 := e1.X*e1.X + e2.X*e1.X + e2.X*e2.X
+		inty2 := e1.Y*e1.Y + e2.Y*e1.Y + e2.Y*e2.Y
+		I += (0.25 / 3.0 * D) * (intx2 + inty2)
+	}
+
+	// The rounding adds a strip Radius wide along each edge and a circular
+	// sector at each vertex, filling the angle between the strips.
+	if r := s.Radius; r > 0.0 {
+		for i := 0; i < n; i++ {
+			v1 := s.Vertices[i].Sub(origin)
+			v2 := s.Vertices[(i+1)%n].Sub(origin)
+			normal := s.Normals[i]
+
+			// Strip along the edge from v1 to v2
+			e := v2.Sub(v1)
+			length := e.Length()
+			stripArea := length * r
+			c := MulSV(0.5, v1.Add(v2))
+			c = c.Add(MulSV(0.5*r, normal))
+			area += stripArea
+			moment = moment.Add(MulSV(stripArea, c))
+			I += stripArea * ((length*length+r*r)/12.0 + Dot(c, c))
+
+			// Sector at v2, between the normals of the edges meeting there
+			next := s.Normals[(i+1)%n]
+			angle := math.Atan2(CrossVV(normal, next), Dot(normal, next))
+			sectorArea := 0.5 * angle * r * r
+			bisector := normal.Add(next)
+			bisector = bisector.Normalize()
+			// Distance from the apex to the centroid
+			d := 4.0 * r * math.Sin(0.5*angle) / (3.0 * angle)
+			c = v2.Add(MulSV(d, bisector))
+			area += sectorArea
+			moment = moment.Add(MulSV(sectorArea, c))
+			// Polar moment about the apex, moved to the centroid, then to origin
+			I += 0.25*angle*r*r*r*r - sectorArea*d*d + sectorArea*Dot(c, c)
+		}
+	}
+
+	center := MulSV(1.0/area, moment)
+
+	m := density * area
+	return MassData{
+		Mass:   m,
+		Center: origin.Add(center),
+		// Shift the inertia from the origin vertex to the centroid.
+		I: density*I - m*Dot(center, center),
+	}
+}
+
+func (s *Polygon) Support(d Vec2) Vec2 {
+	best := 0
+	bestDot := Dot(s.Vertices[0], d)
+	for i := 1; i < len(s.Vertices); i++ {
+		if dot := Dot(s.Vertices[i], d); dot > bestDot {
+			best = i
+			bestDot = dot
+		}
+	}
+	return s.Vertices[best].Add(MulSV(s.Radius, d.Normalize()))
+}
+
+func (s *Polygon) TestPoint(p Vec2, xf Transform) bool {
+	return s.containsLocal(xf.MulTV(p))
+}
+
+// containsLocal reports whether the rounded polygon contains the local point q.
+func (s *Polygon) containsLocal(q Vec2) bool {
+	// A two sided polygon, such as a capsule, has no area but its rounding.
+	// Its half-planes would hold the whole line through it.
+	if len(s.Vertices) == 2 {
+		c, _, _, _ := segmentDistance(s.Vertices[0], s.Vertices[1], q, q)
+		d := q.Sub(c)
+		return Dot(d, d) <= s.Radius*s.Radius
+	}
+
+	separation := -math.MaxFloat64
+	for i, n := range s.Normals {
+		separation = math.Max(separation, Dot(n, q.Sub(s.Vertices[i])))
+	}
+
+	if separation <= 0.0 {
+		return true
+	}
+	if separation > s.Radius {
+		return false
+	}
+
+	// Outside the core, the point may lie off a rounded corner.
+	r := s.Radius
+	n := len(s.Vertices)
+	for i := range s.Vertices {
+		c, _, _, _ := segmentDistance(s.Vertices[i], s.Vertices[(i+1)%n], q, q)
+		if d := q.Sub(c); Dot(d, d) <= r*r {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Polygon) RayCast(input RayCastInput, xf Transform) (RayCastOutput, bool) {
+	return rayCastPolygon(s, input, xf)
+}
+
+// edgeNumber returns the feature number of edge i, wrapping around.
+func (s *Polygon) edgeNumber(i int) EdgeNumbers {
+	n := len(s.Vertices)
+	return EdgeNumbers((i+n)%n + 1)
+}
+
+// boxPolygon returns the polygon with the same geometry and edge numbers as box.
+func boxPolygon(box *Box) *Polygon {
+	h := MulSV(0.5, box.Width)
+	return &Polygon{
+		Vertices: []Vec2{{h.X, h.Y}, {-h.X, h.Y}, {-h.X, -h.Y}, {h.X, -h.Y}},
+		Normals:  []Vec2{{0.0, 1.0}, {-1.0, 0.0}, {0.0, -1.0}, {1.0, 0.0}},
+		Radius:   box.Radius,
+	}
+}
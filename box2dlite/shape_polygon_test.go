@@ -0,0 +1,91 @@
+package box2dlite
+
+import (
+	"math"
+	"testing"
+)
+
+// integrateMass numerically integrates the mass properties of the rounded
+// polygon p over a grid of cells h wide, for density 1.
+func integrateMass(p *Polygon, h float64) MassData {
+	aabb := p.ComputeAABB(NewTransform(Vec2{0.0, 0.0}, 0.0))
+
+	inside := func(q Vec2) bool {
+		// Distance to the core, zero inside it
+		n := len(p.Vertices)
+		out := false
+		d := math.MaxFloat64
+		for i := 0; i < n; i++ {
+			v1, v2 := p.Vertices[i], p.Vertices[(i+1)%n]
+			if Dot(p.Normals[i], q.Sub(v1)) > 0.0 {
+				out = true
+			}
+			e := v2.Sub(v1)
+			t := math.Max(0.0, math.Min(1.0, Dot(q.Sub(v1), e)/Dot(e, e)))
+			w := q.Sub(v1.Add(MulSV(t, e)))
+			d = math.Min(d, w.Length())
+		}
+		return !out || d <= p.Radius
+	}
+
+	var md MassData
+	var moment Vec2
+	var I float64
+	for x := aabb.Min.X + 0.5*h; x < aabb.Max.X; x += h {
+		for y := aabb.Min.Y + 0.5*h; y < aabb.Max.Y; y += h {
+			q := Vec2{x, y}
+			if inside(q) {
+				md.Mass += h * h
+				moment = moment.Add(MulSV(h*h, q))
+				I += h * h * Dot(q, q)
+			}
+		}
+	}
+	md.Center = MulSV(1.0/md.Mass, moment)
+	md.I = I - md.Mass*Dot(md.Center, md.Center)
+	return md
+}
+
+func TestRoundedBoxMass(t *testing.T) {
+	w, h, r := 2.0, 1.0, 0.25
+	md := NewRoundedBox(Vec2{w, h}, r).ComputeMass(2.0)
+
+	area := w*h + 2.0*r*(w+h) + math.Pi*r*r
+	if math.Abs(md.Mass-2.0*area) > 1e-9 {
+		t.Errorf("mass = %v, want %v", md.Mass, 2.0*area)
+	}
+	if !near(md.Center, Vec2{0.0, 0.0}) {
+		t.Errorf("center = %v, want the origin", md.Center)
+	}
+
+	want := integrateMass(boxPolygon(NewRoundedBox(Vec2{w, h}, r)), 0.002)
+	if math.Abs(md.I-2.0*want.I) > 1e-3*want.I {
+		t.Errorf("inertia = %v, want %v", md.I, 2.0*want.I)
+	}
+}
+
+func TestRoundedPolygonMass(t *testing.T) {
+	p := NewRoundedPolygon([]Vec2{{1.0, 0.0}, {0.5, 1.0}, {-0.5, 0.2}}, 0.3)
+	md := p.ComputeMass(1.0)
+	want := integrateMass(p, 0.002)
+
+	if math.Abs(md.Mass-want.Mass) > 1e-3*want.Mass {
+		t.Errorf("mass = %v, want %v", md.Mass, want.Mass)
+	}
+	if d := md.Center.Sub(want.Center); d.Length() > 1e-3 {
+		t.Errorf("center = %v, want %v", md.Center, want.Center)
+	}
+	if math.Abs(md.I-want.I) > 1e-3*want.I {
+		t.Errorf("inertia = %v, want %v", md.I, want.I)
+	}
+}
+
+func TestPolygonMassMatchesBox(t *testing.T) {
+	box := NewBox(Vec2{2.0, 1.0})
+	want := box.ComputeMass(3.0)
+	md := boxPolygon(box).ComputeMass(3.0)
+
+	if math.Abs(md.Mass-want.Mass) > 1e-9 || math.Abs(md.I-want.I) > 1e-9 || !near(md.Center, want.Center) {
+		t.Errorf("polygon mass %+v, want the box's %+v", md, want)
+	}
+}
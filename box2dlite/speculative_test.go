@@ -0,0 +1,105 @@
+package box2dlite
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSpeculativeContactPositions(t *testing.T) {
+	// Surfaces 0.7 apart along x, between x = 0.25 and x = 0.95
+	wall := NewBox(Vec2{0.1, 4.0})
+	xfWall := NewTransform(Vec2{1.0, 0.0}, 0.0)
+	xf := NewTransform(Vec2{0.0, 0.0}, 0.0)
+
+	tests := []struct {
+		name  string
+		shape Shape
+	}{
+		{"box", NewBox(Vec2{0.5, 0.5})},
+		{"circle", NewCircle(0.25)},
+	}
+	for _, tt := range tests {
+		check := func(order string, contacts []*Contact) {
+			if len(contacts) == 0 {
+				t.Errorf("%s %s: no contacts", tt.name, order)
+				return
+			}
+			for _, c := range contacts {
+				if math.Abs(c.Separation-0.7) > 1e-9 {
+					t.Errorf("%s %s: separation = %v, want 0.7", tt.name, order, c.Separation)
+				}
+				if math.Abs(c.Position.X-0.6) > 1e-9 {
+					t.Errorf("%s %s: contact at x = %v, want 0.6 between the surfaces", tt.name, order, c.Position.X)
+				}
+			}
+		}
+		check("first", collideShapesSpeculative(tt.shape, xf, wall, xfWall, 1.0))
+		check("second", collideShapesSpeculative(wall, xfWall, tt.shape, xf, 1.0))
+	}
+}
+
+func TestSpeculativeHitAddsNoSpin(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	w.Speculative = true
+	addBody(w, NewBox(Vec2{0.1, 4.0}), math.MaxFloat64, Vec2{2.0, 0.0})
+	box := addBody(w, NewBox(Vec2{0.5, 0.5}), 1.0, Vec2{0.0, 0.0})
+	box.Velocity = Vec2{60.0, 0.0}
+
+	for i := 0; i < 5; i++ {
+		w.Step(1.0 / 60.0)
+
+		if av := box.AngularVelocity; math.Abs(av) > 0.2 {
+			t.Fatalf("step %d: angular velocity = %v, want none from a head-on hit", i, av)
+		}
+		if vy := box.Velocity.Y; math.Abs(vy) > 0.1 {
+			t.Fatalf("step %d: velocity y = %v, want none from a head-on hit", i, vy)
+		}
+	}
+}
+
+// userCircle is a circle with a custom shape type.
+type userCircle struct {
+	*Circle
+}
+
+func (s userCircle) Type() ShapeType {
+	return SHAPE_USER
+}
+
+func TestSpeculativeRegisteredFuncGetsShapes(t *testing.T) {
+	var gotA, gotB Shape
+	RegisterCollideFunc(SHAPE_USER, SHAPE_BOX, func(shapeA Shape, xfA Transform, shapeB Shape, xfB Transform) []*Contact {
+		gotA, gotB = shapeA, shapeB
+		return nil
+	})
+	defer RegisterCollideFunc(SHAPE_USER, SHAPE_BOX, nil)
+
+	circle := userCircle{NewCircle(0.25)}
+	box := NewBox(Vec2{0.5, 0.5})
+	xf := NewTransform(Vec2{0.0, 0.0}, 0.0)
+	collideShapesSpeculative(circle, xf, box, xf, 1.0)
+
+	if gotA != Shape(circle) || gotB != Shape(box) {
+		t.Errorf("registered function got %v and %v, want the shapes as they are", gotA, gotB)
+	}
+	if box.Radius != 0.0 {
+		t.Errorf("box radius = %v, want it unchanged", box.Radius)
+	}
+}
+
+func TestSpeculativeHitReachesWall(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	w.Speculative = true
+	addBody(w, NewBox(Vec2{0.1, 4.0}), math.MaxFloat64, Vec2{2.0, 0.0})
+	box := addBody(w, NewBox(Vec2{0.5, 0.5}), 1.0, Vec2{0.0, 0.0})
+	box.Velocity = Vec2{60.0, 0.0}
+
+	stepWorld(w, 5)
+
+	if x := box.Position.X + 0.25; math.Abs(x-1.95) > 0.01 {
+		t.Errorf("box face at x = %v, want it against the wall face at 1.95", x)
+	}
+	if vx := box.Velocity.X; math.Abs(vx) > 0.1 {
+		t.Errorf("box velocity x = %v, want it stopped", vx)
+	}
+}
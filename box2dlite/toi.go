@@ -0,0 +1,201 @@
+package box2dlite
+
+import (
+	"math"
+)
+
+// Bullets are swept over each step by conservative advancement: the
+// closest distance to another fixture, divided by a bound on how fast the
+// bullet can close it, is a time the bullet can safely move. Repeating that
+// finds the time of impact, and the bullet is moved back there.
+// See https://box2d.org/files/ErinCatto_ContinuousCollision_GDC2013.pdf
+
+const (
+	toiMaxIterations = 20
+
+	// Distance at which a bullet counts as touching
+	toiTolerance = 0.0025
+
+	// Depth a clamped bullet is left at, so the next step finds the contact
+	// but the solver does not push it out
+	toiPenetration = 0.005
+)
+
+// sweep is the motion of a body's center of mass over a step.
+type sweep struct {
+	localCenter Vec2
+	c0, c1      Vec2
+	a0, a1      float64
+}
+
+func bodySweep(b *Body) sweep {
+	return sweep{
+		localCenter: b.LocalCenter,
+		c0:          b.center0,
+		c1:          b.WorldCenter(),
+		a0:          b.rotation0,
+		a1:          b.Rotation,
+	}
+}
+
+// transform returns the body transform at t in [0, 1] of the step.
+func (s *sweep) transform(t float64) Transform {
+	d := s.c1.Sub(s.c0)
+	c := s.c0.Add(MulSV(t, d))
+	xf := NewTransform(Vec2{0.0, 0.0}, s.a0+t*(s.a1-s.a0))
+	xf.Position = c.Sub(xf.R.MulV(s.localCenter))
+	return xf
+}
+
+// fixtureExtent returns how far the shape of f reaches from the center of
+// mass of its body.
+func fixtureExtent(f *Fixture) float64 {
+	xf := f.LocalTransform()
+	xf.Position = xf.Position.Sub(f.Body.LocalCenter)
+	aabb := f.Shape.ComputeAABB(xf)
+	e := Vec2{
+		math.Max(math.Abs(aabb.Min.X), math.Abs(aabb.Max.X)),
+		math.Max(math.Abs(aabb.Min.Y), math.Abs(aabb.Max.Y)),
+	}
+	return e.Length()
+}
+
+// timeOfImpact returns the first time in the step that fixture fA, moving
+// with sA, touches fixture fB where fB's body ended the step, and the normal
+// from fA to fB there. Fixtures overlapping at the start of the step are
+// left to the contact solver, and one-sided edges are only hit from the front.
+func timeOfImpact(fA *Fixture, sA sweep, fB *Fixture) (float64, Vec2, bool) {
+	xfB := fB.Transform()
+	rA := fixtureExtent(fA)
+
+	// Bound on the closing speed along n over the whole step
+	d := sA.c1.Sub(sA.c0)
+	angular := math.Abs(sA.a1-sA.a0) * rA
+
+	minT := 1.0
+	var normal Vec2
+	hit := false
+
+	for _, childA := range convexChildren(fA.Shape) {
+		proxyA := makeDistanceProxy(childA)
+
+	children:
+		for _, childB := range convexChildren(fB.Shape) {
+			if e, ok := childB.(*Edge); ok && e.OneSided {
+				p := xfB.MulTV(sA.c0)
+				en := e.Vertex2.Sub(e.Vertex1)
+				if Dot(CrossVS(en, 1.0), p.Sub(e.Vertex1)) < 0.0 {
+					continue
+				}
+			}
+			proxyB := makeDistanceProxy(childB)
+
+			t := 0.0
+			var n Vec2
+			for i := 0; i < toiMaxIterations; i++ {
+				xfA := sA.transform(t)
+				xfA = xfA.Mul(fA.LocalTransform())
+				output := gjk(proxyA, xfA, proxyB, xfB)
+
+				if output.Distance == 0.0 {
+					if i == 0 {
+						continue children
+					}
+					break
+				}
+
+				n = output.PointB.Sub(output.PointA)
+				n = n.Normalize()
+				if output.Distance < toiTolerance {
+					break
+				}
+
+				speed := Dot(d, n) + angular
+				if speed <= 0.0 {
+					continue children
+				}
+
+				t += output.Distance / speed
+				if t >= minT {
+					continue children
+				}
+			}
+
+			minT = t
+			normal = n
+			hit = true
+		}
+	}
+
+	return minT, normal, hit
+}
+
+// solveContinuous moves each bullet back to the first time in the step it
+// touches a fixture of another body, leaving it just inside so the next
+// step's contacts stop it. The other bodies are taken where they ended the
+// step, bullets are not swept against each other, and fixtures kept apart
+// by their filters or ShouldCollide are passed through.
+func (w *World) solveContinuous() {
+	synced := false
+
+	for _, b := range w.Bodies {
+		if !b.Bullet || b.invMass == 0.0 {
+			continue
+		}
+
+		// Bring the proxies to the end of the step, once.
+		if !synced {
+			w.syncProxies()
+			synced = true
+		}
+
+		s := bodySweep(b)
+		start := s.transform(0.0)
+		end := s.transform(1.0)
+
+		minT := 1.0
+		var normal Vec2
+		hit := false
+
+		for _, fA := range b.Fixtures {
+			if fA.Sensor {
+				continue
+			}
+
+			xf0 := start.Mul(fA.LocalTransform())
+			xf1 := end.Mul(fA.LocalTransform())
+			swept := fA.Shape.ComputeAABB(xf0)
+			swept = swept.Combine(fA.Shape.ComputeAABB(xf1))
+			// Rotation can swing the shape out of both poses.
+			r := Vec2{fixtureExtent(fA), fixtureExtent(fA)}
+			if s.a0 != s.a1 {
+				swept = swept.Combine(AABB{s.c0.Sub(r), s.c0.Add(r)})
+				swept = swept.Combine(AABB{s.c1.Sub(r), s.c1.Add(r)})
+			}
+
+			w.queryAABB(swept, func(fB *Fixture) bool {
+				if fB.Body == b || fB.Sensor || (fB.Body.Bullet && fB.Body.invMass > 0.0) {
+					return true
+				}
+				if !w.canCollide(fA, fB) {
+					return true
+				}
+
+				if t, n, ok := timeOfImpact(fA, s, fB); ok && t < minT {
+					minT = t
+					normal = n
+					hit = true
+				}
+				return true
+			})
+		}
+
+		if !hit {
+			continue
+		}
+
+		xf := s.transform(minT)
+		b.Position = xf.Position.Add(MulSV(toiTolerance+toiPenetration, normal))
+		b.Rotation = s.a0 + minT*(s.a1-s.a0)
+	}
+}
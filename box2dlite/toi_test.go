@@ -0,0 +1,66 @@
+package box2dlite
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBulletStopsAtWall(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	addBody(w, NewBox(Vec2{0.1, 4.0}), math.MaxFloat64, Vec2{0.0, 0.0})
+	bullet := addBody(w, NewCircle(0.05), 1.0, Vec2{-3.0, 0.0})
+	bullet.Velocity = Vec2{300.0, 0.0}
+	bullet.Bullet = true
+
+	stepWorld(w, 10)
+
+	if x := bullet.Position.X; x > -0.05 || x < -0.1 {
+		t.Errorf("bullet at x = %v, want it against the wall face at -0.1", x)
+	}
+}
+
+func TestFastBodyTunnelsWithoutBullet(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	addBody(w, NewBox(Vec2{0.1, 4.0}), math.MaxFloat64, Vec2{0.0, 0.0})
+	shot := addBody(w, NewCircle(0.05), 1.0, Vec2{-3.0, 0.0})
+	shot.Velocity = Vec2{300.0, 0.0}
+
+	stepWorld(w, 2)
+
+	if x := shot.Position.X; x != 7.0 {
+		t.Errorf("body at x = %v, want 7 through the wall", x)
+	}
+}
+
+func TestFilteredBulletPassesThrough(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	wall := addBody(w, NewBox(Vec2{0.1, 4.0}), math.MaxFloat64, Vec2{0.0, 0.0})
+	wall.SetFilter(Filter{CategoryBits: 0x0002, MaskBits: 0xFFFF &^ 0x0004})
+	bullet := addBody(w, NewCircle(0.05), 1.0, Vec2{-3.0, 0.0})
+	bullet.SetFilter(Filter{CategoryBits: 0x0004, MaskBits: 0xFFFF &^ 0x0002})
+	bullet.Velocity = Vec2{300.0, 0.0}
+	bullet.Bullet = true
+
+	stepWorld(w, 2)
+
+	if x := bullet.Position.X; x != 7.0 {
+		t.Errorf("bullet at x = %v, want 7 past the filtered wall", x)
+	}
+}
+
+func TestShouldCollideBulletPassesThrough(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	addBody(w, NewBox(Vec2{0.1, 4.0}), math.MaxFloat64, Vec2{0.0, 0.0})
+	bullet := addBody(w, NewCircle(0.05), 1.0, Vec2{-3.0, 0.0})
+	bullet.Velocity = Vec2{300.0, 0.0}
+	bullet.Bullet = true
+	w.ShouldCollide = func(fixtureA, fixtureB *Fixture) bool {
+		return fixtureA.Body != bullet && fixtureB.Body != bullet
+	}
+
+	stepWorld(w, 2)
+
+	if x := bullet.Position.X; x != 7.0 {
+		t.Errorf("bullet at x = %v, want 7 past the wall", x)
+	}
+}
@@ -1,22 +1,85 @@
 package box2dlite
 
+import (
+	"math"
+)
+
 var accumulateImpulses bool = true
 var warmStarting bool = true
 var positionCorrection bool = true
 
+// Gap at which speculative contacts start, beyond what the bodies can close
+// in a step, so resting contacts are found before the bodies touch
+const speculativeDistance = 0.02
+
 type World struct {
 	Bodies     []*Body
 	Joints     []*Joint
 	Arbiters   map[ArbiterKey]*Arbiter
 	Gravity    Vec2
 	Iterations int
+
+	// Speculative makes the world also create contacts between shapes that
+	// are apart but close enough to meet within the step. The solver lets
+	// them close the gap and no more, which keeps fast bodies from passing
+	// through thin ones more cheaply than bullets do. Pairs where neither
+	// shape has a radius, such as edges against custom shapes, only get
+	// contacts once they touch.
+	Speculative bool
+
+	// ShouldCollide, if set, is asked about each pair of fixtures whose
+	// filters let them collide, before they are collided. Returning false
+	// keeps them apart. Call Refilter when its answer for a body changes.
+	ShouldCollide func(fixtureA, fixtureB *Fixture) bool
+
+	// ContactListener, if set, hears about the arbiters as they change.
+	ContactListener ContactListener
+
+	// Bodies approaching slower than this do not bounce, so resting stacks
+	// stay still. NewWorld sets it to 1.
+	RestitutionThreshold float64
+
+	// Time step of the current step, for the speculative margin
+	dt float64
+
+	// Sensor pairs that overlap, and the changes not yet handed out
+	sensors     map[ArbiterKey]bool
+	sensorBegin []SensorEvent
+	sensorEnd   []SensorEvent
+
+	// Broad-phase state: a proxy per fixture and the fixture pairs whose
+	// fat AABBs overlap.
+	broadPhase BroadPhase
+	proxies    map[*Fixture]*fixtureProxy
+	pairs      map[ArbiterKey]bool
+	stamp      int
+}
+
+// fixtureProxy tracks the broad-phase proxy of a fixture.
+type fixtureProxy struct {
+	id     int
+	aabb   AABB
+	static bool
+
+	// placement of the fixture the aabb was computed at, and how far
+	// ahead of it the proxy reaches
+	xf    Transform
+	reach Vec2
+
+	// step the fixture was last seen in the world
+	stamp int
 }
 
 func NewWorld(g Vec2, i int) *World {
 	return &World{
-		Arbiters:   make(map[ArbiterKey]*Arbiter),
-		Gravity:    g,
-		Iterations: i,
+		Arbiters:             make(map[ArbiterKey]*Arbiter),
+		Gravity:              g,
+		Iterations:           i,
+		RestitutionThreshold: 1.0,
+		broadPhase:           NewTreeBroadPhase(),
+		proxies:              make(map[*Fixture]*fixtureProxy),
+		pairs:                make(map[ArbiterKey]bool),
+		sensors:              make(map[ArbiterKey]bool),
 	}
 }
 
@@ -28,43 +91,290 @@ func (w *World) AddJoint(j *Joint) {
 	w.Joints = append(w.Joints, j)
 }
 
+// Clear removes all bodies and joints. Their arbiters and sensor overlaps
+// end as if the bodies had left the world.
 func (w *World) Clear() {
 	w.Bodies = nil
 	w.Joints = nil
+	w.clearBroadPhase()
+}
+
+// SetBroadPhase makes the world find its pairs with bp, which must hold no
+// proxies. The fixtures move over at the next step, which starts without
+// arbiters: the current ones end here. Pick the broad-phase before stepping.
+func (w *World) SetBroadPhase(bp BroadPhase) {
+	w.clearBroadPhase()
+	w.broadPhase = bp
+}
+
+// clearBroadPhase removes the proxies, pairs and arbiters of all fixtures,
+// ending the arbiters and sensor overlaps.
+func (w *World) clearBroadPhase() {
+	for key := range w.pairs {
+		w.removePair(key)
+	}
+	for key := range w.Arbiters {
+		w.destroyArbiter(key)
+	}
+
+	if w.broadPhase != nil {
+		for _, p := range w.proxies {
+			w.broadPhase.DestroyProxy(p.id)
+		}
+	}
+	w.proxies = make(map[*Fixture]*fixtureProxy)
+	w.pairs = make(map[ArbiterKey]bool)
 	w.Arbiters = make(map[ArbiterKey]*Arbiter)
+	w.sensors = make(map[ArbiterKey]bool)
 }
 
+// BroadPhase keeps the fixtures in the broad-phase. Fixtures whose fat
+// AABBs start to overlap become pairs if their filters let them collide,
+// and only pairs are collided. Pairs with a sensor track the overlap
+// instead of getting an arbiter. A pair ends, along with its arbiter or
+// overlap, when the fat AABBs part.
 func (w *World) BroadPhase() {
-	// O(n^2) broad-phase
-	for i, bi := range w.Bodies {
-		for _, bj := range w.Bodies[i+1:] {
+	w.updateProxies()
 
-			if bi.invMass == 0.0 && bj.invMass == 0.0 {
-				continue
-			}
+	// Find the new pairs of the proxies that moved.
+	w.broadPhase.UpdatePairs(func(userDataA, userDataB interface{}) {
+		w.addPair(userDataA.(*Fixture), userDataB.(*Fixture))
+	})
+
+	// Update the contacts of the pairs.
+	for key := range w.pairs {
+		// ArbiterKey.Set() orders Fixture1 and Fixture2.
+		// Use key.Fixture1, key.Fixture2.
+		p1 := w.proxies[key.Fixture1]
+		p2 := w.proxies[key.Fixture2]
+		fat1 := w.broadPhase.FatAABB(p1.id)
+		fat2 := w.broadPhase.FatAABB(p2.id)
+
+		if !fat1.Overlaps(fat2) || (p1.static && p2.static) {
+			w.removePair(key)
+			continue
+		}
+
+		// Sensors only track the overlap.
+		if key.Fixture1.Sensor || key.Fixture2.Sensor {
+			w.destroyArbiter(key)
+			f1, f2 := key.Fixture1, key.Fixture2
+			overlapping := p1.aabb.Overlaps(p2.aabb) && len(collideShapes(f1.Shape, p1.xf, f2.Shape, p2.xf)) > 0
+			w.setSensorOverlap(key, overlapping)
+			continue
+		}
+		w.setSensorOverlap(key, false)
+
+		margin := 0.0
+		if w.Speculative {
+			margin = w.speculativeMargin(key.Fixture1, key.Fixture2)
+		}
 
-			var key ArbiterKey
-			key.Set(bi, bj)
-			// ArbiterKey.Set() orders Body1 and Body2.
-			// Then don't using bi and bj.
-			// Use key.Body1, key.Body2.
-			contacts := Collide(key.Body1, key.Body2)
-
-			if len(contacts) > 0 {
-				if it, ok := w.Arbiters[key]; !ok {
-					var a Arbiter
-					a.Set(bi, bj, contacts)
-					w.Arbiters[key] = &a
-				} else {
-					it.Update(contacts)
+		var contacts []*Contact
+		if grown := p1.aabb.Grow(margin); grown.Overlaps(p2.aabb) {
+			f1, f2 := key.Fixture1, key.Fixture2
+			contacts = collideShapesSpeculative(f1.Shape, p1.xf, f2.Shape, p2.xf, margin)
+		}
+
+		if len(contacts) > 0 {
+			if it, ok := w.Arbiters[key]; !ok {
+				var a Arbiter
+				a.Set(key.Fixture1, key.Fixture2, contacts)
+				w.Arbiters[key] = &a
+				if w.ContactListener != nil {
+					w.ContactListener.BeginContact(&a)
 				}
 			} else {
-				delete(w.Arbiters, key)
+				it.Update(contacts)
+			}
+		} else {
+			w.destroyArbiter(key)
+		}
+	}
+}
+
+// updateProxies creates, moves and destroys the broad-phase proxies to
+// match the fixtures of the bodies in the world.
+func (w *World) updateProxies() {
+	w.syncProxies()
+
+	// Drop the fixtures that left the world.
+	for f, p := range w.proxies {
+		if p.stamp == w.stamp {
+			continue
+		}
+
+		w.broadPhase.DestroyProxy(p.id)
+		delete(w.proxies, f)
+
+		for key := range w.pairs {
+			if key.Fixture1 == f || key.Fixture2 == f {
+				w.removePair(key)
+			}
+		}
+	}
+}
+
+// syncProxies creates and moves the broad-phase proxies of the fixtures in
+// the world and stamps them. The proxies of fixtures that left the world
+// keep an older stamp until updateProxies drops them with their pairs.
+func (w *World) syncProxies() {
+	if w.broadPhase == nil {
+		w.broadPhase = NewTreeBroadPhase()
+		w.proxies = make(map[*Fixture]*fixtureProxy)
+	}
+	if w.pairs == nil {
+		w.pairs = make(map[ArbiterKey]bool)
+	}
+	if w.sensors == nil {
+		w.sensors = make(map[ArbiterKey]bool)
+	}
+
+	w.stamp++
+
+	for _, b := range w.Bodies {
+		static := b.invMass == 0.0
+		for _, f := range b.Fixtures {
+			xf := f.Transform()
+
+			// Reach as far as the body moves in a step, so the pairs for
+			// speculative contacts exist.
+			var reach Vec2
+			if w.Speculative {
+				reach = MulSV(w.dt, b.Velocity)
+			}
+
+			p, ok := w.proxies[f]
+			if !ok {
+				p = &fixtureProxy{aabb: f.Shape.ComputeAABB(xf)}
+				p.id = w.broadPhase.CreateProxy(sweptAABB(p.aabb, reach), f)
+				w.proxies[f] = p
+			} else {
+				// Only fixtures that moved need a new AABB.
+				if xf != p.xf || reach != p.reach {
+					aabb := p.aabb
+					if xf != p.xf {
+						aabb = f.Shape.ComputeAABB(xf)
+					}
+					displacement := aabb.Center()
+					displacement = displacement.Sub(p.aabb.Center())
+					w.broadPhase.MoveProxy(p.id, sweptAABB(aabb, reach), displacement)
+					p.aabb = aabb
+				}
+				// A body that stops being static needs its pairs back.
+				if p.static && !static {
+					w.broadPhase.TouchProxy(p.id)
+				}
+			}
+
+			p.xf = xf
+			p.reach = reach
+			p.static = static
+			p.stamp = w.stamp
+		}
+	}
+}
+
+// inWorld reports whether the fixture of a proxy was in the world at the
+// last syncProxies.
+func (w *World) inWorld(f *Fixture) bool {
+	p, ok := w.proxies[f]
+	return ok && p.stamp == w.stamp
+}
+
+// sweptAABB returns aabb combined with itself moved by d.
+func sweptAABB(aabb AABB, d Vec2) AABB {
+	return aabb.Combine(AABB{aabb.Min.Add(d), aabb.Max.Add(d)})
+}
+
+// removePair ends a pair along with its arbiter or sensor overlap.
+func (w *World) removePair(key ArbiterKey) {
+	delete(w.pairs, key)
+	w.destroyArbiter(key)
+	w.setSensorOverlap(key, false)
+}
+
+// destroyArbiter deletes the arbiter of a pair, if it has one.
+func (w *World) destroyArbiter(key ArbiterKey) {
+	a, ok := w.Arbiters[key]
+	if !ok {
+		return
+	}
+
+	delete(w.Arbiters, key)
+	if w.ContactListener != nil {
+		w.ContactListener.EndContact(a)
+	}
+}
+
+// canCollide reports whether the filters and the ShouldCollide callback
+// let two fixtures collide.
+func (w *World) canCollide(f1, f2 *Fixture) bool {
+	if !shouldCollide(f1, f2) {
+		return false
+	}
+	return w.ShouldCollide == nil || w.ShouldCollide(f1, f2)
+}
+
+// Refilter runs the filters again for the fixtures of b, after their Filter
+// or what ShouldCollide decides for them changed. Pairs no longer allowed
+// end now, and newly allowed ones start at the next step.
+func (w *World) Refilter(b *Body) {
+	for _, f := range b.Fixtures {
+		p, ok := w.proxies[f]
+		if !ok {
+			// Filtered when it enters the broad-phase
+			continue
+		}
+
+		for key := range w.pairs {
+			if key.Fixture1 != f && key.Fixture2 != f {
+				continue
+			}
+			if !w.canCollide(key.Fixture1, key.Fixture2) {
+				w.removePair(key)
 			}
 		}
+
+		// Look for the pairs again.
+		w.broadPhase.TouchProxy(p.id)
 	}
 }
 
+// speculativeMargin returns how far apart two fixtures may be and still
+// meet within the step: the distance their bodies can close at their
+// current velocities, plus speculativeDistance.
+func (w *World) speculativeMargin(f1, f2 *Fixture) float64 {
+	b1, b2 := f1.Body, f2.Body
+	dv := b2.Velocity.Sub(b1.Velocity)
+	speed := dv.Length()
+	speed += math.Abs(b1.AngularVelocity) * fixtureExtent(f1)
+	speed += math.Abs(b2.AngularVelocity) * fixtureExtent(f2)
+	return speculativeDistance + w.dt*speed
+}
+
+func (w *World) addPair(f1, f2 *Fixture) {
+	if f1.Body == f2.Body {
+		return
+	}
+
+	if f1.Sensor && f2.Sensor {
+		return
+	}
+
+	if f1.Body.invMass == 0.0 && f2.Body.invMass == 0.0 {
+		return
+	}
+
+	if !w.canCollide(f1, f2) {
+		return
+	}
+
+	var key ArbiterKey
+	key.Set(f1, f2)
+	w.pairs[key] = true
+}
+
 func (w *World) Step(dt float64) {
 	var inv_dt float64
 	if dt > 0.0 {
@@ -72,6 +382,7 @@ func (w *World) Step(dt float64) {
 	}
 
 	// Determine overlapping bodies and update contact points.
+	w.dt = dt
 	w.BroadPhase()
 
 	// Integrate forces.
@@ -84,9 +395,25 @@ func (w *World) Step(dt float64) {
 		b.AngularVelocity += dt * b.invI * b.Torque
 	}
 
+	// Let the listener adjust the arbiters.
+	for _, arb := range w.Arbiters {
+		arb.Enabled = true
+		if w.ContactListener != nil {
+			w.ContactListener.PreSolve(arb)
+		}
+	}
+
+	for _, arb := range w.Arbiters {
+		if arb.Enabled {
+			arb.StoreRelativeVelocity(w.RestitutionThreshold)
+		}
+	}
+
 	// Perform pre-steps.
 	for _, arb := range w.Arbiters {
-		arb.PreStep(inv_dt)
+		if arb.Enabled {
+			arb.PreStep(inv_dt)
+		}
 	}
 
 	for _, j := range w.Joints {
@@ -96,7 +423,9 @@ func (w *World) Step(dt float64) {
 	// Perform iterations
 	for i := 0; i < w.Iterations; i++ {
 		for _, arb := range w.Arbiters {
-			arb.ApplyImpulse()
+			if arb.Enabled {
+				arb.ApplyImpulse()
+			}
 		}
 
 		for _, j := range w.Joints {
@@ -104,12 +433,29 @@ func (w *World) Step(dt float64) {
 		}
 	}
 
+	if w.ContactListener != nil {
+		for _, arb := range w.Arbiters {
+			if arb.Enabled {
+				w.ContactListener.PostSolve(arb)
+			}
+		}
+	}
+
 	// Integrate Velocities
 	for _, b := range w.Bodies {
-		b.Position = b.Position.Add(MulSV(dt, b.Velocity))
+		// Move the center of mass and rotate about it.
+		c := b.WorldCenter()
+		b.center0 = c
+		b.rotation0 = b.Rotation
+		c = c.Add(MulSV(dt, b.Velocity))
 		b.Rotation += dt * b.AngularVelocity
+		R := Mat22ByAngle(b.Rotation)
+		b.Position = c.Sub(R.MulV(b.LocalCenter))
 
 		b.Force = Vec2{0.0, 0.0}
 		b.Torque = 0.0
 	}
+
+	// Move bullets back to their first impact.
+	w.solveContinuous()
 }
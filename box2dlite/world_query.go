@@ -0,0 +1,211 @@
+package box2dlite
+
+import (
+	"sort"
+)
+
+// World queries go through the broad-phase proxies. Each query first creates
+// and moves them, so it sees bodies added or moved since the last Step.
+// Pairs and arbiters are left for Step to update.
+
+// RayHit is where a ray hits a fixture.
+type RayHit struct {
+	Body    *Body
+	Fixture *Fixture
+	Point   Vec2
+	Normal  Vec2
+
+	// Fraction of the way from p1 to p2
+	Fraction float64
+}
+
+// RayCastCallback is called for each fixture a ray hits, in no particular
+// order. Its result steers the cast: -1 ignores the hit and goes on, 0 stops,
+// a fraction cuts the ray there, and 1 goes on without cutting it.
+// Return hit.Fraction to find the closest hit.
+type RayCastCallback func(hit RayHit) float64
+
+// RayCast casts the ray from p1 to p2 and reports the fixtures it hits.
+// Rays starting inside a shape do not hit it.
+func (w *World) RayCast(p1, p2 Vec2, callback RayCastCallback) {
+	input := RayCastInput{P1: p1, P2: p2, MaxFraction: 1.0}
+
+	rayCastFixture := func(input RayCastInput, f *Fixture) float64 {
+		output, ok := f.Shape.RayCast(input, f.Transform())
+		if !ok {
+			return input.MaxFraction
+		}
+
+		d := p2.Sub(p1)
+		return callback(RayHit{
+			Body:     f.Body,
+			Fixture:  f,
+			Point:    p1.Add(MulSV(output.Fraction, d)),
+			Normal:   output.Normal,
+			Fraction: output.Fraction,
+		})
+	}
+
+	w.syncProxies()
+	w.broadPhase.RayCast(input, func(input RayCastInput, id int) float64 {
+		f := w.broadPhase.UserData(id).(*Fixture)
+		if !w.inWorld(f) {
+			return -1.0
+		}
+		return rayCastFixture(input, f)
+	})
+}
+
+// RayCastClosest returns the first hit along the ray from p1 to p2.
+func (w *World) RayCastClosest(p1, p2 Vec2) (RayHit, bool) {
+	var closest RayHit
+	found := false
+
+	w.RayCast(p1, p2, func(hit RayHit) float64 {
+		closest = hit
+		found = true
+		return hit.Fraction
+	})
+
+	return closest, found
+}
+
+// RayCastAll returns all the hits along the ray from p1 to p2, nearest first.
+func (w *World) RayCastAll(p1, p2 Vec2) []RayHit {
+	var hits []RayHit
+
+	w.RayCast(p1, p2, func(hit RayHit) float64 {
+		hits = append(hits, hit)
+		return 1.0
+	})
+
+	sort.Slice(hits, func(i, j int) bool {
+		return hits[i].Fraction < hits[j].Fraction
+	})
+
+	return hits
+}
+
+// QueryAABB calls callback for each fixture whose bounding box overlaps
+// aabb, until the callback returns false.
+func (w *World) QueryAABB(aabb AABB, callback func(f *Fixture) bool) {
+	w.syncProxies()
+	w.queryAABB(aabb, callback)
+}
+
+// queryAABB is QueryAABB with the proxies as the last syncProxies left them.
+func (w *World) queryAABB(aabb AABB, callback func(f *Fixture) bool) {
+	w.broadPhase.Query(aabb, func(id int) bool {
+		f := w.broadPhase.UserData(id).(*Fixture)
+		if p := w.proxies[f]; p.stamp == w.stamp && p.aabb.Overlaps(aabb) {
+			return callback(f)
+		}
+		return true
+	})
+}
+
+// QueryPoint returns the bodies with a fixture containing p.
+func (w *World) QueryPoint(p Vec2) []*Body {
+	var bodies []*Body
+
+	w.QueryAABB(AABB{p, p}, func(f *Fixture) bool {
+		if !f.Shape.TestPoint(p, f.Transform()) {
+			return true
+		}
+
+		for _, b := range bodies {
+			if b == f.Body {
+				return true
+			}
+		}
+		bodies = append(bodies, f.Body)
+		return true
+	})
+
+	return bodies
+}
+
+// OverlapShape calls callback for each fixture that shape placed at xf
+// touches, until the callback returns false. Shapes are tested with the
+// same narrow-phase as the simulation, so a shape behind a one-sided edge
+// does not touch it. No arbiters are created.
+func (w *World) OverlapShape(shape Shape, xf Transform, callback func(f *Fixture) bool) {
+	w.QueryAABB(shape.ComputeAABB(xf), func(f *Fixture) bool {
+		if len(collideShapes(f.Shape, f.Transform(), shape, xf)) > 0 {
+			return callback(f)
+		}
+		return true
+	})
+}
+
+// CastShape sweeps shape from xf along translation and reports the
+// fixtures it runs into, like RayCast. The fraction of a hit is how far the
+// shape can move before touching the fixture, and the normal points from
+// the fixture toward the shape. Fixtures touching the shape at xf are hit
+// at fraction 0, and one-sided edges are only hit from the front. No
+// arbiters are created. Chains and heightfields count as their edges;
+// other shapes must be convex.
+func (w *World) CastShape(shape Shape, xf Transform, translation Vec2, callback RayCastCallback) {
+	start := shape.ComputeAABB(xf)
+	end := shape.ComputeAABB(Transform{xf.Position.Add(translation), xf.R})
+	maxFraction := 1.0
+
+	w.QueryAABB(start.Combine(end), func(f *Fixture) bool {
+		fxf := f.Transform()
+
+		hit := RayHit{
+			Body:    f.Body,
+			Fixture: f,
+		}
+		found := false
+
+		if contacts := collideShapes(f.Shape, fxf, shape, xf); len(contacts) > 0 {
+			for _, c := range contacts {
+				hit.Point = hit.Point.Add(c.Position)
+				hit.Normal = hit.Normal.Add(c.Normal)
+			}
+			hit.Point = MulSV(1.0/float64(len(contacts)), hit.Point)
+			hit.Normal = hit.Normal.Normalize()
+			found = true
+		} else {
+			for _, a := range convexChildren(f.Shape) {
+				proxyA := makeDistanceProxy(a)
+				for _, b := range convexChildren(shape) {
+					t, output, ok := castProxies(proxyA, fxf, makeDistanceProxy(b), xf, translation, maxFraction)
+					// Overlapping at xf without touching, as behind a
+					// one-sided edge.
+					if !ok || output.Distance == 0.0 || (found && t >= hit.Fraction) {
+						continue
+					}
+
+					normal := output.PointB.Sub(output.PointA)
+					normal = normal.Normalize()
+					if edge, ok := a.(*Edge); ok && edge.OneSided {
+						front := CrossVS(edge.Vertex2.Sub(edge.Vertex1), 1.0)
+						if Dot(normal, fxf.R.MulV(front)) < 0.0 {
+							continue
+						}
+					}
+
+					hit.Fraction = t
+					hit.Point = output.PointA
+					hit.Normal = normal
+					found = true
+				}
+			}
+		}
+
+		if !found {
+			return true
+		}
+
+		value := callback(hit)
+		if value == 0.0 {
+			return false
+		}
+		if value > 0.0 {
+			maxFraction = value
+		}
+		return true
+	})
+}
@@ -0,0 +1,227 @@
+package box2dlite
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRayCastClosest(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	first := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{2.0, 0.0})
+	addBody(w, NewCircle(0.5), 1.0, Vec2{5.0, 0.0})
+
+	hit, ok := w.RayCastClosest(Vec2{-5.0, 0.0}, Vec2{10.0, 0.0})
+	if !ok || hit.Body != first {
+		t.Fatalf("RayCastClosest hit %v, want the near box", hit.Body)
+	}
+	if !near(hit.Point, Vec2{1.5, 0.0}) || !near(hit.Normal, Vec2{-1.0, 0.0}) {
+		t.Errorf("hit at %v with normal %v, want (1.5, 0) and (-1, 0)", hit.Point, hit.Normal)
+	}
+
+	if hits := w.RayCastAll(Vec2{-5.0, 0.0}, Vec2{10.0, 0.0}); len(hits) != 2 || hits[0].Body != first {
+		t.Errorf("RayCastAll found %d hits, want 2 with the near box first", len(hits))
+	}
+}
+
+func TestRayCastSeesBodiesAddedAfterStep(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	addBody(w, NewBox(Vec2{20.0, 1.0}), math.MaxFloat64, Vec2{0.0, -10.0})
+	stepWorld(w, 1)
+
+	box := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{5.0, 5.0})
+
+	if hit, ok := w.RayCastClosest(Vec2{0.0, 5.0}, Vec2{10.0, 5.0}); !ok || hit.Body != box {
+		t.Errorf("RayCastClosest hit %v, want the new box", hit.Body)
+	}
+}
+
+func TestRayCastLeavesPairsToStep(t *testing.T) {
+	w := NewWorld(Vec2{0.0, -10.0}, 10)
+	addBody(w, NewBox(Vec2{20.0, 1.0}), math.MaxFloat64, Vec2{0.0, -0.5})
+	box := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{0.0, 0.5})
+	stepWorld(w, 1)
+
+	removeBody(w, box)
+
+	if hit, ok := w.RayCastClosest(Vec2{-5.0, 0.5}, Vec2{5.0, 0.5}); ok {
+		t.Errorf("RayCastClosest hit %v, which left the world", hit.Body)
+	}
+	if len(w.Arbiters) != 1 {
+		t.Errorf("RayCast changed the arbiters to %d, want 1 until Step", len(w.Arbiters))
+	}
+
+	stepWorld(w, 1)
+	if len(w.Arbiters) != 0 {
+		t.Errorf("Step left %d arbiters, want 0", len(w.Arbiters))
+	}
+}
+
+func TestRayCastAlongCapsuleAxis(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	capsule := addBody(w, NewCapsule(Vec2{-1.0, 0.0}, Vec2{1.0, 0.0}, 0.5), 1.0, Vec2{0.0, 5.0})
+
+	hit, ok := w.RayCastClosest(Vec2{-5.0, 5.0}, Vec2{5.0, 5.0})
+	if !ok || hit.Body != capsule {
+		t.Fatalf("RayCastClosest hit %v, want the capsule", hit.Body)
+	}
+	if !near(hit.Point, Vec2{-1.5, 5.0}) || !near(hit.Normal, Vec2{-1.0, 0.0}) {
+		t.Errorf("hit at %v with normal %v, want (-1.5, 5) and (-1, 0)", hit.Point, hit.Normal)
+	}
+
+	// Starting inside the capsule does not hit it.
+	if _, ok := w.RayCastClosest(Vec2{0.0, 5.0}, Vec2{5.0, 5.0}); ok {
+		t.Error("RayCastClosest from inside the capsule hit it")
+	}
+}
+
+func TestQueriesSeeBodiesAddedAfterStep(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	addBody(w, NewBox(Vec2{20.0, 1.0}), math.MaxFloat64, Vec2{0.0, -10.0})
+	stepWorld(w, 1)
+
+	box := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{5.0, 5.0})
+	capsule := addBody(w, NewCapsule(Vec2{-1.0, 0.0}, Vec2{1.0, 0.0}, 0.5), 1.0, Vec2{-5.0, 5.0})
+
+	if bodies := w.QueryPoint(Vec2{5.0, 5.0}); len(bodies) != 1 || bodies[0] != box {
+		t.Errorf("QueryPoint found %v, want the new box", bodies)
+	}
+	if bodies := w.QueryPoint(Vec2{-3.6, 5.0}); len(bodies) != 1 || bodies[0] != capsule {
+		t.Errorf("QueryPoint found %v, want the new capsule", bodies)
+	}
+	if bodies := w.QueryPoint(Vec2{-3.6, 5.4}); len(bodies) != 0 {
+		t.Errorf("QueryPoint off the capsule end found %v", bodies)
+	}
+
+	var found []*Fixture
+	w.QueryAABB(AABB{Vec2{-10.0, 4.0}, Vec2{10.0, 6.0}}, func(f *Fixture) bool {
+		found = append(found, f)
+		return true
+	})
+	if len(found) != 2 {
+		t.Errorf("QueryAABB found %d fixtures, want 2", len(found))
+	}
+}
+
+func TestQueryAABBLeavesPairsToStep(t *testing.T) {
+	w := NewWorld(Vec2{0.0, -10.0}, 10)
+	addBody(w, NewBox(Vec2{20.0, 1.0}), math.MaxFloat64, Vec2{0.0, -0.5})
+	box := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{0.0, 0.5})
+	stepWorld(w, 1)
+
+	removeBody(w, box)
+
+	if bodies := w.QueryPoint(Vec2{0.0, 0.5}); len(bodies) != 0 {
+		t.Errorf("QueryPoint found %v, which left the world", bodies)
+	}
+	if len(w.Arbiters) != 1 {
+		t.Errorf("QueryPoint changed the arbiters to %d, want 1 until Step", len(w.Arbiters))
+	}
+}
+
+func TestOverlapShape(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	ground := addBody(w, NewBox(Vec2{20.0, 1.0}), math.MaxFloat64, Vec2{0.0, -0.5})
+	addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{5.0, 5.0})
+
+	overlaps := func(position Vec2) []*Body {
+		var bodies []*Body
+		w.OverlapShape(NewCircle(0.5), NewTransform(position, 0.0), func(f *Fixture) bool {
+			bodies = append(bodies, f.Body)
+			return true
+		})
+		return bodies
+	}
+
+	if bodies := overlaps(Vec2{0.0, 0.3}); len(bodies) != 1 || bodies[0] != ground {
+		t.Errorf("circle sunk in the ground overlaps %v, want the ground", bodies)
+	}
+	// The AABBs overlap but the shapes do not.
+	if bodies := overlaps(Vec2{4.0, 4.0}); len(bodies) != 0 {
+		t.Errorf("circle off the box corner overlaps %v", bodies)
+	}
+	if len(w.Arbiters) != 0 {
+		t.Errorf("OverlapShape made %d arbiters", len(w.Arbiters))
+	}
+}
+
+func TestCastShape(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	ground := addBody(w, NewBox(Vec2{20.0, 1.0}), math.MaxFloat64, Vec2{0.0, -0.5})
+	addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{5.0, 5.0})
+
+	var hits []RayHit
+	w.CastShape(NewCircle(0.5), NewTransform(Vec2{0.0, 5.0}, 0.0), Vec2{0.0, -10.0}, func(hit RayHit) float64 {
+		hits = append(hits, hit)
+		return hit.Fraction
+	})
+
+	if len(hits) != 1 || hits[0].Body != ground {
+		t.Fatalf("CastShape found %d hits, want the ground", len(hits))
+	}
+
+	// The circle touches the ground after moving 4.5.
+	hit := hits[0]
+	if math.Abs(10.0*hit.Fraction-4.5) > 0.01 {
+		t.Errorf("hit at fraction %v, want 0.45", hit.Fraction)
+	}
+	if math.Abs(hit.Normal.X) > 1.0e-6 || math.Abs(hit.Normal.Y-1.0) > 1.0e-6 {
+		t.Errorf("hit normal %v, want (0, 1)", hit.Normal)
+	}
+	if math.Abs(hit.Point.X) > 0.01 || math.Abs(hit.Point.Y) > 0.01 {
+		t.Errorf("hit point %v, want (0, 0)", hit.Point)
+	}
+
+	// A shape already touching is hit at once.
+	hits = hits[:0]
+	w.CastShape(NewBox(Vec2{1.0, 1.0}), NewTransform(Vec2{0.0, 0.4}, 0.0), Vec2{10.0, 0.0}, func(hit RayHit) float64 {
+		hits = append(hits, hit)
+		return 1.0
+	})
+	if len(hits) != 1 || hits[0].Fraction != 0.0 {
+		t.Errorf("touching cast found %v, want the ground at fraction 0", hits)
+	}
+}
+
+func TestCastShapeGrazesSmallFixture(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	pebble := addBody(w, NewCircle(0.05), math.MaxFloat64, Vec2{5.0, 0.54})
+
+	hit := RayHit{Fraction: -1.0}
+	w.CastShape(NewCircle(0.5), NewTransform(Vec2{0.0, 0.0}, 0.0), Vec2{10.0, 0.0}, func(h RayHit) float64 {
+		hit = h
+		return h.Fraction
+	})
+
+	if hit.Body != pebble {
+		t.Fatalf("CastShape missed the pebble")
+	}
+	// The circles touch when the centers are 0.55 apart.
+	want := 5.0 - math.Sqrt(0.55*0.55-0.54*0.54)
+	if math.Abs(10.0*hit.Fraction-want) > 0.01 {
+		t.Errorf("hit at %v, want %v", 10.0*hit.Fraction, want)
+	}
+}
+
+func TestCastShapeOneSidedEdge(t *testing.T) {
+	w := NewWorld(Vec2{0.0, 0.0}, 10)
+	edge := NewOneSidedEdge(Vec2{6.0, 0.0}, Vec2{5.0, 0.0}, Vec2{-5.0, 0.0}, Vec2{-6.0, 0.0})
+	addBody(w, edge, math.MaxFloat64, Vec2{0.0, 0.0})
+
+	cast := func(from, translation Vec2) []RayHit {
+		var hits []RayHit
+		w.CastShape(NewBox(Vec2{1.0, 1.0}), NewTransform(from, 0.0), translation, func(hit RayHit) float64 {
+			hits = append(hits, hit)
+			return 1.0
+		})
+		return hits
+	}
+
+	hits := cast(Vec2{0.0, 3.0}, Vec2{0.0, -6.0})
+	if len(hits) != 1 || math.Abs(6.0*hits[0].Fraction-2.5) > 0.01 || !near(hits[0].Normal, Vec2{0.0, 1.0}) {
+		t.Errorf("cast from the front found %v, want a hit after 2.5 with normal (0, 1)", hits)
+	}
+
+	if hits := cast(Vec2{0.0, -3.0}, Vec2{0.0, 6.0}); len(hits) != 0 {
+		t.Errorf("cast from behind found %v", hits)
+	}
+}
@@ -0,0 +1,84 @@
+package box2dlite
+
+import (
+	"math"
+	"testing"
+)
+
+// addBody adds a body made of shape s with mass m at position to w.
+// Pass math.MaxFloat64 as the mass for a static body.
+func addBody(w *World, s Shape, m float64, position Vec2) *Body {
+	b := &Body{}
+	b.SetShape(s, m)
+	b.Position = position
+	w.AddBody(b)
+	return b
+}
+
+// removeBody takes b out of w.Bodies.
+func removeBody(w *World, b *Body) {
+	for i, o := range w.Bodies {
+		if o == b {
+			w.Bodies = append(w.Bodies[:i], w.Bodies[i+1:]...)
+			return
+		}
+	}
+}
+
+// stepWorld steps w n times at 60 Hz.
+func stepWorld(w *World, n int) {
+	for i := 0; i < n; i++ {
+		w.Step(1.0 / 60.0)
+	}
+}
+
+func TestShouldCollideKeepsBodiesApart(t *testing.T) {
+	w := NewWorld(Vec2{0.0, -10.0}, 10)
+	addBody(w, NewBox(Vec2{20.0, 1.0}), math.MaxFloat64, Vec2{0.0, -0.5})
+	ghost := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{0.0, 1.0})
+	box := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{3.0, 1.0})
+
+	var asked bool
+	w.ShouldCollide = func(fixtureA, fixtureB *Fixture) bool {
+		asked = true
+		return fixtureA.Body != ghost && fixtureB.Body != ghost
+	}
+
+	stepWorld(w, 60)
+
+	if !asked {
+		t.Error("ShouldCollide was never asked")
+	}
+	if ghost.Position.Y > -1.0 {
+		t.Errorf("rejected box at y = %v, want it through the ground", ghost.Position.Y)
+	}
+	if math.Abs(box.Position.Y-0.5) > 0.05 {
+		t.Errorf("box at y = %v, want it resting on the ground at 0.5", box.Position.Y)
+	}
+}
+
+func TestRefilter(t *testing.T) {
+	w := NewWorld(Vec2{0.0, -10.0}, 10)
+	addBody(w, NewBox(Vec2{20.0, 1.0}), math.MaxFloat64, Vec2{0.0, -0.5})
+	box := addBody(w, NewBox(Vec2{1.0, 1.0}), 1.0, Vec2{0.0, 0.49})
+	stepWorld(w, 1)
+
+	if len(w.Arbiters) != 1 {
+		t.Fatalf("world has %d arbiters, want the box on the ground", len(w.Arbiters))
+	}
+
+	// Pairs no longer allowed end at once.
+	box.SetFilter(Filter{CategoryBits: 0x0002, MaskBits: 0x0002})
+	w.Refilter(box)
+	if len(w.Arbiters) != 0 {
+		t.Errorf("Refilter left %d arbiters, want 0", len(w.Arbiters))
+	}
+
+	// Allowed pairs come back at the next step.
+	box.SetFilter(DefaultFilter())
+	w.Refilter(box)
+	stepWorld(w, 1)
+	if len(w.Arbiters) != 1 {
+		t.Errorf("world has %d arbiters after refiltering back, want 1", len(w.Arbiters))
+	}
+}
@@ -455,6 +455,49 @@ func (app *App) Demo9() {
 
 }
 
+// Mixed shapes
+func (app *App) Demo10() {
+	app.World.Clear()
+
+	var b1 b2d.Body
+	b1.Set(&b2d.Vec2{100.0, 20.0}, math.MaxFloat64)
+	b1.Position = b2d.Vec2{0.0, -0.5 * b1.Width.Y}
+	app.World.AddBody(&b1)
+
+	var ramp b2d.Body
+	ramp.Set(&b2d.Vec2{12.0, 0.25}, math.MaxFloat64)
+	ramp.Position = b2d.Vec2{-3.0, 6.0}
+	ramp.Rotation = -0.25
+	app.World.AddBody(&ramp)
+
+	var slope b2d.Body
+	slope.SetShape(b2d.NewChain([]b2d.Vec2{{16.0, 9.0}, {13.0, 4.5}, {10.0, 1.5}, {6.0, 0.0}}), math.MaxFloat64)
+	app.World.AddBody(&slope)
+
+	var hills b2d.Body
+	hills.SetShape(b2d.NewHeightfield([]float64{6.0, 3.0, 1.5, 2.0, 0.5, 1.0, 0.0}, 1.5), math.MaxFloat64)
+	hills.Position = b2d.Vec2{-19.0, 0.0}
+	app.World.AddBody(&hills)
+
+	shapes := []b2d.Shape{
+		b2d.NewCircle(0.5),
+		b2d.NewBox(b2d.Vec2{1.0, 1.0}),
+		b2d.NewCircle(0.3),
+		b2d.NewPolygon([]b2d.Vec2{{0.6, -0.4}, {0.0, 0.6}, {-0.6, -0.4}}),
+		b2d.NewPolygon([]b2d.Vec2{{0.5, 0.0}, {0.25, 0.43}, {-0.25, 0.43}, {-0.5, 0.0}, {-0.25, -0.43}, {0.25, -0.43}}),
+		b2d.NewCapsule(b2d.Vec2{-0.5, 0.0}, b2d.Vec2{0.5, 0.0}, 0.3),
+		b2d.NewRoundedBox(b2d.Vec2{0.8, 0.8}, 0.15),
+		b2d.NewRoundedPolygon([]b2d.Vec2{{0.5, -0.3}, {0.0, 0.5}, {-0.5, -0.3}}, 0.15),
+	}
+
+	for i := 0; i < 16; i++ {
+		var b b2d.Body
+		b.SetShape(shapes[i%len(shapes)], 5.0)
+		b.Position = b2d.Vec2{-8.0 + 1.2*float64(i%8), 9.0 + 1.5*float64(i/8)}
+		app.World.AddBody(&b)
+	}
+}
+
 func (app *App) ProcessEvent(e interface{}) {
 	// fmt.Println(reflect.TypeOf(e), e)
 
@@ -481,6 +524,8 @@ func (app *App) ProcessEvent(e interface{}) {
 			app.Demo8()
 		case sdl.K_9:
 			app.Demo9()
+		case sdl.K_0:
+			app.Demo10()
 		}
 	}
 }
@@ -492,22 +537,120 @@ func (app *App) OnUpdate(ms uint32) {
 func (app *App) RenderBody(b *b2d.Body) {
 	app.Renderer.SetDrawColor(0xff, 0xff, 0xff, 0xff)
 
-	R := b2d.Mat22ByAngle(b.Rotation)
-	x := b.Position
-	h := b2d.MulSV(0.5, b.Width)
+	for _, f := range b.Fixtures {
+		app.RenderShape(f.Transform(), f.Shape)
+	}
+}
 
+func (app *App) RenderShape(xf b2d.Transform, shape b2d.Shape) {
+	switch s := shape.(type) {
+	case *b2d.Box:
+		h := b2d.MulSV(0.5, s.Width)
+		app.RenderRoundedPolygon(xf, []b2d.Vec2{{-h.X, -h.Y}, {h.X, -h.Y}, {h.X, h.Y}, {-h.X, h.Y}}, s.Radius)
+	case *b2d.Polygon:
+		app.RenderRoundedPolygon(xf, s.Vertices, s.Radius)
+	case *b2d.Circle:
+		app.RenderCircle(xf, b2d.Vec2{0.0, 0.0}, s.Radius)
+	case *b2d.Capsule:
+		app.RenderCapsule(xf, s.Center1, s.Center2, s.Radius)
+	case *b2d.Edge:
+		app.RenderPolyline(xf, []b2d.Vec2{s.Vertex1, s.Vertex2})
+	case *b2d.Chain:
+		vertices := s.Vertices
+		if s.Loop {
+			vertices = append(vertices[:len(vertices):len(vertices)], vertices[0])
+		}
+		app.RenderPolyline(xf, vertices)
+	case *b2d.Heightfield:
+		vertices := make([]b2d.Vec2, len(s.Heights))
+		for i := range vertices {
+			vertices[i] = s.Vertex(i)
+		}
+		app.RenderPolyline(xf, vertices)
+	}
+}
+
+func (app *App) RenderPolyline(xf b2d.Transform, vertices []b2d.Vec2) {
 	o := b2d.Vec2{400, 400}
 	S := b2d.Mat22{b2d.Vec2{20.0, 0.0}, b2d.Vec2{0.0, -20.0}}
 
-	v1 := o.Add(S.MulV(x.Add(R.MulV(b2d.Vec2{-h.X, -h.Y}))))
-	v2 := o.Add(S.MulV(x.Add(R.MulV(b2d.Vec2{h.X, -h.Y}))))
-	v3 := o.Add(S.MulV(x.Add(R.MulV(b2d.Vec2{h.X, h.Y}))))
-	v4 := o.Add(S.MulV(x.Add(R.MulV(b2d.Vec2{-h.X, h.Y}))))
+	for i := 0; i+1 < len(vertices); i++ {
+		v1 := o.Add(S.MulV(xf.MulV(vertices[i])))
+		v2 := o.Add(S.MulV(xf.MulV(vertices[i+1])))
+		app.Renderer.DrawLine(int(v1.X), int(v1.Y), int(v2.X), int(v2.Y))
+	}
+}
+
+func (app *App) RenderCapsule(xf b2d.Transform, c1, c2 b2d.Vec2, radius float64) {
+	const segments = 8
+
+	d := c2.Sub(c1)
+	angle := math.Atan2(d.Y, d.X)
+
+	var vertices []b2d.Vec2
+	for _, end := range []struct {
+		center b2d.Vec2
+		start  float64
+	}{{c2, angle - 0.5*math.Pi}, {c1, angle + 0.5*math.Pi}} {
+		for i := 0; i <= segments; i++ {
+			a := end.start + math.Pi*float64(i)/segments
+			vertices = append(vertices, end.center.Add(b2d.Vec2{radius * math.Cos(a), radius * math.Sin(a)}))
+		}
+	}
+	app.RenderPolygon(xf, vertices)
+}
+
+// RenderRoundedPolygon draws counter-clockwise vertices grown by radius, with arcs at the corners.
+func (app *App) RenderRoundedPolygon(xf b2d.Transform, vertices []b2d.Vec2, radius float64) {
+	const segments = 4
+
+	if radius <= 0.0 {
+		app.RenderPolygon(xf, vertices)
+		return
+	}
 
-	app.Renderer.DrawLine(int(v1.X), int(v1.Y), int(v2.X), int(v2.Y))
-	app.Renderer.DrawLine(int(v2.X), int(v2.Y), int(v3.X), int(v3.Y))
-	app.Renderer.DrawLine(int(v3.X), int(v3.Y), int(v4.X), int(v4.Y))
-	app.Renderer.DrawLine(int(v4.X), int(v4.Y), int(v1.X), int(v1.Y))
+	n := len(vertices)
+	var outline []b2d.Vec2
+	for i, v := range vertices {
+		prev := v.Sub(vertices[(i+n-1)%n])
+		next := vertices[(i+1)%n].Sub(v)
+		// Outward normals point to the right of the edges.
+		start := math.Atan2(-prev.X, prev.Y)
+		end := math.Atan2(-next.X, next.Y)
+		if end < start {
+			end += 2.0 * math.Pi
+		}
+		for j := 0; j <= segments; j++ {
+			a := start + (end-start)*float64(j)/segments
+			outline = append(outline, v.Add(b2d.Vec2{radius * math.Cos(a), radius * math.Sin(a)}))
+		}
+	}
+	app.RenderPolygon(xf, outline)
+}
+
+func (app *App) RenderCircle(xf b2d.Transform, center b2d.Vec2, radius float64) {
+	const segments = 16
+
+	vertices := make([]b2d.Vec2, segments)
+	for i := range vertices {
+		angle := 2.0 * math.Pi * float64(i) / segments
+		vertices[i] = center.Add(b2d.Vec2{radius * math.Cos(angle), radius * math.Sin(angle)})
+	}
+	app.RenderPolygon(xf, vertices)
+
+	// Draw a radius so rolling is visible.
+	app.RenderPolygon(xf, []b2d.Vec2{center, vertices[0]})
+}
+
+func (app *App) RenderPolygon(xf b2d.Transform, vertices []b2d.Vec2) {
+	o := b2d.Vec2{400, 400}
+	S := b2d.Mat22{b2d.Vec2{20.0, 0.0}, b2d.Vec2{0.0, -20.0}}
+
+	for i := range vertices {
+		v1 := o.Add(S.MulV(xf.MulV(vertices[i])))
+		v2 := o.Add(S.MulV(xf.MulV(vertices[(i+1)%len(vertices)])))
+		app.Renderer.DrawLine(int(v1.X), int(v1.Y), int(v2.X), int(v2.Y))
+	}
 }
 
 func (app *App) RenderJoint(j *b2d.Joint) {